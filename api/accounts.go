@@ -2,11 +2,181 @@ package api
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/pkg/errors"
 )
 
+const (
+	// Valid account status values
+	AccountStatusActive = "active"
+	AccountStatusLocked = "locked"
+
+	// CHAP secrets must be between 12 and 16 characters long
+	CHAPSecretMinLength = 12
+	CHAPSecretMaxLength = 16
+)
+
+const chapSecretCharset = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func NewCHAPSecret(secret string) *CHAPSecret {
+	return &CHAPSecret{Secret: secret}
+}
+
+// GenerateCHAPSecret returns a random CHAPSecretMaxLength character secret from crypto/rand
+func GenerateCHAPSecret() (result *CHAPSecret, err error) {
+	max := big.NewInt(int64(len(chapSecretCharset)))
+	secret := make([]byte, CHAPSecretMaxLength)
+	for i := range secret {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return nil, errors.Wrap(err, "generating CHAP secret")
+		}
+		secret[i] = chapSecretCharset[n.Int64()]
+	}
+	return NewCHAPSecret(string(secret)), nil
+}
+
+func (s CHAPSecret) Validate() error {
+	if len(s.Secret) < CHAPSecretMinLength || len(s.Secret) > CHAPSecretMaxLength {
+		return BuildRequestError(ErrInvalidCHAPSecret,
+			fmt.Sprintf("CHAP secret must be between %d and %d characters", CHAPSecretMinLength, CHAPSecretMaxLength))
+	}
+	return nil
+}
+
+// The cluster expects CHAP secrets as plain strings
+func (s CHAPSecret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Secret)
+}
+
+func (s *CHAPSecret) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &s.Secret)
+}
+
+func (s CHAPSecret) String() string {
+	return "********"
+}
+
+func validateCHAPSecrets(initiator *CHAPSecret, target *CHAPSecret) error {
+	for _, s := range []*CHAPSecret{initiator, target} {
+		if s == nil {
+			continue
+		}
+		if err := s.Validate(); err != nil {
+			return err
+		}
+	}
+	if initiator != nil && target != nil && initiator.Secret == target.Secret {
+		return BuildRequestError(ErrInvalidCHAPSecret, "Initiator and target CHAP secrets must be different")
+	}
+	return nil
+}
+
 func (c *Client) ListAccounts(ctx context.Context, req ListAccountsRequest) (result []Account, err error) {
 	lar := ListAccountsResult{}
 	err = c.request(ctx, "ListAccounts", req, &lar)
 	result = lar.Accounts
 	return result, err
 }
+
+// AddAccount creates a tenant account. Secrets left nil are generated by the cluster.
+func (c *Client) AddAccount(ctx context.Context, req AddAccountRequest) (result *Account, err error) {
+	if err = validateCHAPSecrets(req.InitiatorSecret, req.TargetSecret); err != nil {
+		return nil, err
+	}
+	aar := AddAccountResult{}
+	err = c.request(ctx, "AddAccount", req, &aar)
+	if err != nil {
+		return nil, err
+	}
+	result = &aar.Account
+	return result, err
+}
+
+func (c *Client) ModifyAccount(ctx context.Context, req ModifyAccountRequest) (result *Account, err error) {
+	if err = validateCHAPSecrets(req.InitiatorSecret, req.TargetSecret); err != nil {
+		return nil, err
+	}
+	mar := ModifyAccountResult{}
+	err = c.request(ctx, "ModifyAccount", req, &mar)
+	if err != nil {
+		return nil, err
+	}
+	result = &mar.Account
+	return result, err
+}
+
+// RemoveAccount removes an account. All volumes of the account must be deleted and purged first.
+func (c *Client) RemoveAccount(ctx context.Context, id int64) (err error) {
+	req := RemoveAccountRequest{
+		AccountID: id,
+	}
+	return c.request(ctx, "RemoveAccount", req, nil)
+}
+
+func (c *Client) GetAccountByID(ctx context.Context, id int64) (result *Account, err error) {
+	req := GetAccountByIDRequest{
+		AccountID: id,
+	}
+	gar := GetAccountResult{}
+	err = c.request(ctx, "GetAccountByID", req, &gar)
+	if err != nil {
+		return nil, err
+	}
+	result = &gar.Account
+	return result, err
+}
+
+func (c *Client) GetAccountByName(ctx context.Context, username string) (result *Account, err error) {
+	req := GetAccountByNameRequest{
+		Username: username,
+	}
+	gar := GetAccountResult{}
+	err = c.request(ctx, "GetAccountByName", req, &gar)
+	if err != nil {
+		return nil, err
+	}
+	result = &gar.Account
+	return result, err
+}
+
+func (c *Client) GetAccountEfficiency(ctx context.Context, id int64) (result *GetEfficiencyResult, err error) {
+	req := GetAccountEfficiencyRequest{
+		AccountID: id,
+	}
+	ger := GetEfficiencyResult{}
+	err = c.request(ctx, "GetAccountEfficiency", req, &ger)
+	if err != nil {
+		return nil, err
+	}
+	result = &ger
+	return result, err
+}
+
+// RotateAccountCHAPSecrets replaces both CHAP secrets of an account with newly generated ones.
+// Sessions already logged in are not affected until they reconnect.
+func (c *Client) RotateAccountCHAPSecrets(ctx context.Context, id int64) (result *Account, err error) {
+	initiator, err := GenerateCHAPSecret()
+	if err != nil {
+		return nil, err
+	}
+	target, err := GenerateCHAPSecret()
+	if err != nil {
+		return nil, err
+	}
+	for target.Secret == initiator.Secret {
+		if target, err = GenerateCHAPSecret(); err != nil {
+			return nil, err
+		}
+	}
+	req := ModifyAccountRequest{
+		AccountID:       id,
+		InitiatorSecret: initiator,
+		TargetSecret:    target,
+	}
+	return c.ModifyAccount(ctx, req)
+}
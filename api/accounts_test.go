@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccountName = "solidfire-sdk-test"
+
+var testAccount = map[string]interface{}{
+	"accountID":       testAccountId,
+	"username":        testAccountName,
+	"status":          AccountStatusActive,
+	"volumes":         []int64{testVolumeId},
+	"initiatorSecret": "initiatorsecret1",
+	"targetSecret":    "targetsecret123",
+	"attributes":      map[string]interface{}{},
+}
+
+func TestCHAPSecretJSON(t *testing.T) {
+	req := AddAccountRequest{
+		Username:        testAccountName,
+		InitiatorSecret: NewCHAPSecret("initiatorsecret1"),
+	}
+	data, err := json.Marshal(req)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"username":"solidfire-sdk-test","initiatorSecret":"initiatorsecret1"}`, string(data))
+
+	secret := CHAPSecret{}
+	require.Nil(t, json.Unmarshal([]byte(`"targetsecret123"`), &secret))
+	require.Equal(t, "targetsecret123", secret.Secret)
+}
+
+func TestGenerateCHAPSecret(t *testing.T) {
+	s1, err := GenerateCHAPSecret()
+	require.Nil(t, err)
+	require.Nil(t, s1.Validate())
+	s2, err := GenerateCHAPSecret()
+	require.Nil(t, err)
+	require.NotEqual(t, s1.Secret, s2.Secret)
+}
+
+func TestAddAccount(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"AccountID": testAccountId, "Account": testAccount})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := AddAccountRequest{
+		Username:        testAccountName,
+		InitiatorSecret: NewCHAPSecret("initiatorsecret1"),
+		TargetSecret:    NewCHAPSecret("targetsecret123"),
+	}
+	resp, err := c.AddAccount(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, testAccountId, resp.AccountID)
+	require.Equal(t, testAccountName, resp.Username)
+	require.Equal(t, "initiatorsecret1", resp.InitiatorSecret)
+	require.Len(t, *requests, 1)
+	require.Equal(t, "AddAccount", (*requests)[0].Method)
+	require.Equal(t, "initiatorsecret1", (*requests)[0].Params["initiatorSecret"])
+	require.Equal(t, "targetsecret123", (*requests)[0].Params["targetSecret"])
+}
+
+func TestAddAccountInvalidSecret(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"AccountID": testAccountId, "Account": testAccount})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := AddAccountRequest{
+		Username:        testAccountName,
+		InitiatorSecret: NewCHAPSecret("short"),
+	}
+	_, err := c.AddAccount(ctx, req)
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrInvalidCHAPSecret, reqErr.Name)
+
+	req.InitiatorSecret = NewCHAPSecret("samesecret1234")
+	req.TargetSecret = NewCHAPSecret("samesecret1234")
+	_, err = c.AddAccount(ctx, req)
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrInvalidCHAPSecret, reqErr.Name)
+	require.Len(t, *requests, 0)
+}
+
+func TestModifyAccount(t *testing.T) {
+	c := getTestClient(t)
+	testAccount2 := make(map[string]interface{})
+	for k, v := range testAccount {
+		testAccount2[k] = v
+	}
+	testAccount2["status"] = AccountStatusLocked
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"Account": testAccount2})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := ModifyAccountRequest{
+		AccountID: testAccountId,
+		Status:    AccountStatusLocked,
+	}
+	resp, err := c.ModifyAccount(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, testAccountId, resp.AccountID)
+	require.Equal(t, AccountStatusLocked, resp.Status)
+}
+
+func TestRemoveAccount(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	err := c.RemoveAccount(ctx, testAccountId)
+	require.Nil(t, err)
+	require.Equal(t, "RemoveAccount", (*requests)[0].Method)
+	require.Equal(t, float64(testAccountId), (*requests)[0].Params["accountID"])
+}
+
+func TestGetAccountByID(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"Account": testAccount})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetAccountByID(ctx, testAccountId)
+	require.Nil(t, err)
+	require.Equal(t, testAccountId, resp.AccountID)
+	require.Equal(t, testAccountName, resp.Username)
+	require.Equal(t, []int64{testVolumeId}, resp.Volumes)
+}
+
+func TestGetAccountByIDError(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := SFResponse{
+		Error: SFAPIError{
+			Code:    500,
+			Name:    ErrAccountIDDoesNotExist,
+			Message: "Account 1 does not exist",
+		},
+	}
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	_, err := c.GetAccountByID(ctx, testAccountId)
+	var notFound *ResourceNotFoundError
+	require.True(t, errors.As(err, &notFound))
+	require.Equal(t, ErrAccountIDDoesNotExist, notFound.Name)
+}
+
+func TestGetAccountByName(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"Account": testAccount})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetAccountByName(ctx, testAccountName)
+	require.Nil(t, err)
+	require.Equal(t, testAccountId, resp.AccountID)
+	require.Equal(t, testAccountName, (*requests)[0].Params["username"])
+}
+
+func TestGetAccountEfficiency(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{
+		"Compression":      1.5,
+		"Deduplication":    2.25,
+		"ThinProvisioning": 4.0,
+		"Timestamp":        "2021-05-21T17:44:29Z",
+		"MissingVolumes":   []int64{},
+	})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetAccountEfficiency(ctx, testAccountId)
+	require.Nil(t, err)
+	require.Equal(t, 1.5, resp.Compression)
+	require.Equal(t, 2.25, resp.Deduplication)
+	require.Equal(t, 4.0, resp.ThinProvisioning)
+}
+
+func TestRotateAccountCHAPSecrets(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"Account": testAccount})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	_, err := c.RotateAccountCHAPSecrets(ctx, testAccountId)
+	require.Nil(t, err)
+	require.Len(t, *requests, 1)
+	params := (*requests)[0].Params
+	require.Equal(t, "ModifyAccount", (*requests)[0].Method)
+	initiator, ok := params["initiatorSecret"].(string)
+	require.True(t, ok)
+	target, ok := params["targetSecret"].(string)
+	require.True(t, ok)
+	require.Len(t, initiator, CHAPSecretMaxLength)
+	require.Len(t, target, CHAPSecretMaxLength)
+	require.NotEqual(t, initiator, target)
+}
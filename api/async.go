@@ -0,0 +1,330 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Valid GetAsyncResult status values
+	AsyncResultStatusRunning  = "running"
+	AsyncResultStatusComplete = "complete"
+)
+
+// default async wait options
+const (
+	defaultAsyncPollInterval    = time.Second
+	defaultAsyncMaxPollInterval = time.Second * 30
+	defaultAsyncBackoffFactor   = 1.5
+)
+
+type AsyncWaitOptions struct {
+	// Delay before the second poll. The first poll happens immediately.
+	PollInterval time.Duration
+	// Upper bound for the poll delay once backoff has been applied
+	MaxPollInterval time.Duration
+	// Multiplier applied to the poll delay after every poll. A value of 1 disables backoff.
+	BackoffFactor float64
+	// Optional limit on the overall wait, in addition to any deadline on the context
+	Timeout time.Duration
+	// Ask the cluster to keep the result around after it has been returned
+	KeepResult bool
+	// Called with every non-terminal poll result
+	Progress func(GetAsyncResult)
+}
+
+func (o *AsyncWaitOptions) setDefaults() {
+	if o.PollInterval <= 0 {
+		o.PollInterval = defaultAsyncPollInterval
+	}
+	if o.MaxPollInterval <= 0 {
+		o.MaxPollInterval = defaultAsyncMaxPollInterval
+	}
+	if o.MaxPollInterval < o.PollInterval {
+		o.MaxPollInterval = o.PollInterval
+	}
+	if o.BackoffFactor < 1 {
+		o.BackoffFactor = defaultAsyncBackoffFactor
+	}
+}
+
+// AsyncResultError is returned when an async operation completes with an error
+type AsyncResultError struct {
+	AsyncResultID AsyncResultID `json:"asyncResultID"`
+	ResultType    string        `json:"resultType"`
+	Message       string        `json:"message"`
+	Name          string        `json:"name"`
+}
+
+func (e *AsyncResultError) Error() string {
+	return fmt.Sprintf("async result %d (%s) failed: %s : %s", e.AsyncResultID, e.ResultType, e.Name, e.Message)
+}
+func (e *AsyncResultError) GetName() string    { return e.Name }
+func (e *AsyncResultError) GetMessage() string { return e.Message }
+
+func buildAsyncResultError(id AsyncResultID, r GetAsyncResult) *AsyncResultError {
+	asyncErr := &AsyncResultError{
+		AsyncResultID: id,
+		ResultType:    r.ResultType,
+	}
+	switch e := r.Error.(type) {
+	case string:
+		asyncErr.Message = e
+	default:
+		// Best effort, the error object should always have a name and message
+		if err := mapstructure.Decode(e, asyncErr); err != nil {
+			asyncErr.Message = fmt.Sprintf("%v", e)
+		}
+	}
+	return asyncErr
+}
+
+// WaitForAsyncResult polls GetAsyncResult for the given handle until the operation completes.
+// The handles returned by bulk volume, clone, copy and node/drive addition calls can all be
+// waited on by converting them to an AsyncResultID.
+//
+// On success the terminal GetAsyncResult is returned and, when result is non-nil, its Result
+// value is decoded into result. If the operation itself failed an *AsyncResultError is returned.
+func (c *Client) WaitForAsyncResult(ctx context.Context, id AsyncResultID, opts AsyncWaitOptions, result interface{}) (status GetAsyncResult, err error) {
+	opts.setDefaults()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	req := GetAsyncResultRequest{
+		AsyncHandle: id,
+		KeepResult:  opts.KeepResult,
+	}
+	interval := opts.PollInterval
+	for {
+		status, err = c.GetAsyncTask(ctx, req)
+		if err != nil {
+			return status, err
+		}
+		if status.Status == AsyncResultStatusComplete {
+			break
+		}
+		if opts.Progress != nil {
+			opts.Progress(status)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status, errors.Wrapf(ctx.Err(), "waiting for async result %d", id)
+		case <-timer.C:
+		}
+		interval = time.Duration(float64(interval) * opts.BackoffFactor)
+		if interval > opts.MaxPollInterval {
+			interval = opts.MaxPollInterval
+		}
+	}
+
+	if status.Error != nil {
+		return status, buildAsyncResultError(id, status)
+	}
+	if result != nil && status.Result != nil {
+		if err = mapstructure.Decode(status.Result, result); err != nil {
+			return status, errors.Wrapf(err, "decoding async result %d", id)
+		}
+	}
+	return status, nil
+}
+
+// AsyncResultDecodeError is returned when the data carried by an async result can't be decoded
+// into the Go type registered for its ResultType
+type AsyncResultDecodeError struct {
+	ResultType string
+	Message    string
+	Err        error
+}
+
+func (e *AsyncResultDecodeError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("decoding %s async result: %s: %s", e.ResultType, e.Message, e.Err)
+	}
+	return fmt.Sprintf("decoding %s async result: %s", e.ResultType, e.Message)
+}
+func (e *AsyncResultDecodeError) Unwrap() error { return e.Err }
+
+var (
+	asyncResultTypesMu sync.RWMutex
+	asyncResultTypes   = map[string]func() interface{}{
+		BulkVolume:      func() interface{} { return &BulkVolumeAsyncResult{} },
+		Clone:           func() interface{} { return &CloneAsyncResult{} },
+		DriveAdd:        func() interface{} { return &DriveAddAsyncResult{} },
+		DriveRemoval:    func() interface{} { return &DriveRemovalAsyncResult{} },
+		RtfiPendingNode: func() interface{} { return &RtfiPendingNodeAsyncResult{} },
+	}
+)
+
+// RegisterAsyncResultType makes Decode return the value built by factory for async results
+// of the given type. factory must return a pointer to a new struct. Registering a type twice panics.
+func RegisterAsyncResultType(resultType string, factory func() interface{}) {
+	asyncResultTypesMu.Lock()
+	defer asyncResultTypesMu.Unlock()
+	if factory == nil {
+		panic("api: RegisterAsyncResultType factory is nil")
+	}
+	if _, dup := asyncResultTypes[resultType]; dup {
+		panic("api: RegisterAsyncResultType called twice for " + resultType)
+	}
+	asyncResultTypes[resultType] = factory
+}
+
+// decodeAsyncValue decodes each of the given values, in order, into a new instance of the type
+// registered for resultType. Later values override fields set by earlier ones.
+func decodeAsyncValue(resultType string, values ...interface{}) (interface{}, error) {
+	asyncResultTypesMu.RLock()
+	factory, ok := asyncResultTypes[resultType]
+	asyncResultTypesMu.RUnlock()
+	if !ok {
+		return nil, &AsyncResultDecodeError{ResultType: resultType, Message: "no type registered"}
+	}
+	out := factory()
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if err := mapstructure.Decode(v, out); err != nil {
+			return nil, &AsyncResultDecodeError{ResultType: resultType, Message: "invalid data", Err: err}
+		}
+	}
+	return out, nil
+}
+
+func checkAsyncResultType(actual string, expected string) error {
+	if actual != expected {
+		return &AsyncResultDecodeError{ResultType: actual, Message: fmt.Sprintf("expected result type %s", expected)}
+	}
+	return nil
+}
+
+// Decode returns the Details and Result of the async result merged into the Go type registered
+// for its ResultType. While the operation is running only the details are populated.
+func (r GetAsyncResult) Decode() (interface{}, error) {
+	return decodeAsyncValue(r.ResultType, r.Details, r.Result)
+}
+
+func (r GetAsyncResult) AsBulkVolume() (result *BulkVolumeAsyncResult, err error) {
+	if err = checkAsyncResultType(r.ResultType, BulkVolume); err != nil {
+		return nil, err
+	}
+	v, err := r.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*BulkVolumeAsyncResult), nil
+}
+
+func (r GetAsyncResult) AsClone() (result *CloneAsyncResult, err error) {
+	if err = checkAsyncResultType(r.ResultType, Clone); err != nil {
+		return nil, err
+	}
+	v, err := r.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*CloneAsyncResult), nil
+}
+
+func (r GetAsyncResult) AsDriveAdd() (result *DriveAddAsyncResult, err error) {
+	if err = checkAsyncResultType(r.ResultType, DriveAdd); err != nil {
+		return nil, err
+	}
+	v, err := r.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*DriveAddAsyncResult), nil
+}
+
+func (r GetAsyncResult) AsDriveRemoval() (result *DriveRemovalAsyncResult, err error) {
+	if err = checkAsyncResultType(r.ResultType, DriveRemoval); err != nil {
+		return nil, err
+	}
+	v, err := r.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*DriveRemovalAsyncResult), nil
+}
+
+func (r GetAsyncResult) AsRtfiPendingNode() (result *RtfiPendingNodeAsyncResult, err error) {
+	if err = checkAsyncResultType(r.ResultType, RtfiPendingNode); err != nil {
+		return nil, err
+	}
+	v, err := r.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*RtfiPendingNodeAsyncResult), nil
+}
+
+// Decode returns the Data of the async handle decoded into the Go type registered for its ResultType
+func (h AsyncHandle) Decode() (interface{}, error) {
+	return decodeAsyncValue(h.ResultType, h.Data)
+}
+
+func (h AsyncHandle) AsBulkVolume() (result *BulkVolumeAsyncResult, err error) {
+	if err = checkAsyncResultType(h.ResultType, BulkVolume); err != nil {
+		return nil, err
+	}
+	v, err := h.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*BulkVolumeAsyncResult), nil
+}
+
+func (h AsyncHandle) AsClone() (result *CloneAsyncResult, err error) {
+	if err = checkAsyncResultType(h.ResultType, Clone); err != nil {
+		return nil, err
+	}
+	v, err := h.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*CloneAsyncResult), nil
+}
+
+func (h AsyncHandle) AsDriveAdd() (result *DriveAddAsyncResult, err error) {
+	if err = checkAsyncResultType(h.ResultType, DriveAdd); err != nil {
+		return nil, err
+	}
+	v, err := h.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*DriveAddAsyncResult), nil
+}
+
+func (h AsyncHandle) AsDriveRemoval() (result *DriveRemovalAsyncResult, err error) {
+	if err = checkAsyncResultType(h.ResultType, DriveRemoval); err != nil {
+		return nil, err
+	}
+	v, err := h.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*DriveRemovalAsyncResult), nil
+}
+
+func (h AsyncHandle) AsRtfiPendingNode() (result *RtfiPendingNodeAsyncResult, err error) {
+	if err = checkAsyncResultType(h.ResultType, RtfiPendingNode); err != nil {
+		return nil, err
+	}
+	v, err := h.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*RtfiPendingNodeAsyncResult), nil
+}
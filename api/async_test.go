@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const testAsyncResultId AsyncResultID = 42
+
+var testAsyncWaitOptions = AsyncWaitOptions{
+	PollInterval:    time.Millisecond,
+	MaxPollInterval: time.Millisecond * 2,
+}
+
+func buildAsyncStatus(status string, extra map[string]interface{}) SFResponse {
+	result := map[string]interface{}{
+		"status":         status,
+		"resultType":     Clone,
+		"createTime":     "2021-05-24T15:18:14Z",
+		"lastUpdateTime": "2021-05-24T15:18:20Z",
+	}
+	for k, v := range extra {
+		result[k] = v
+	}
+	return buildSFResponseWrapper(result)
+}
+
+func TestWaitForAsyncResult(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMockSequence(c,
+		buildAsyncStatus(AsyncResultStatusRunning, map[string]interface{}{"details": map[string]interface{}{"volumeID": testVolumeId}}),
+		buildAsyncStatus(AsyncResultStatusRunning, nil),
+		buildAsyncStatus(AsyncResultStatusComplete, map[string]interface{}{
+			"result": map[string]interface{}{"cloneID": 7, "volumeID": testVolumeId, "message": "Clone complete."},
+		}),
+	)
+	defer mockReset()
+
+	progressCalls := 0
+	opts := testAsyncWaitOptions
+	opts.Progress = func(r GetAsyncResult) {
+		progressCalls++
+		require.Equal(t, AsyncResultStatusRunning, r.Status)
+	}
+	result := struct {
+		CloneID  int64
+		VolumeID int64
+		Message  string
+	}{}
+	status, err := c.WaitForAsyncResult(context.Background(), testAsyncResultId, opts, &result)
+	require.Nil(t, err)
+	require.Equal(t, AsyncResultStatusComplete, status.Status)
+	require.Equal(t, 2, progressCalls)
+	require.Equal(t, int64(7), result.CloneID)
+	require.Equal(t, testVolumeId, result.VolumeID)
+	require.Equal(t, "Clone complete.", result.Message)
+}
+
+func TestWaitForAsyncResultFailure(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMockSequence(c,
+		buildAsyncStatus(AsyncResultStatusComplete, map[string]interface{}{
+			"error": map[string]interface{}{"name": "xCloneFailed", "message": "Clone failed."},
+		}),
+	)
+	defer mockReset()
+
+	_, err := c.WaitForAsyncResult(context.Background(), testAsyncResultId, testAsyncWaitOptions, nil)
+	require.NotNil(t, err)
+	var asyncErr *AsyncResultError
+	require.True(t, errors.As(err, &asyncErr))
+	require.Equal(t, testAsyncResultId, asyncErr.AsyncResultID)
+	require.Equal(t, Clone, asyncErr.ResultType)
+	require.Equal(t, "xCloneFailed", asyncErr.GetName())
+	require.Equal(t, "Clone failed.", asyncErr.GetMessage())
+}
+
+func TestWaitForAsyncResultTimeout(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMockSequence(c, buildAsyncStatus(AsyncResultStatusRunning, nil))
+	defer mockReset()
+
+	opts := testAsyncWaitOptions
+	opts.Timeout = time.Millisecond * 20
+	_, err := c.WaitForAsyncResult(context.Background(), testAsyncResultId, opts, nil)
+	require.NotNil(t, err)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.True(t, IsContextError(err))
+}
+
+func TestGetAsyncResultAsClone(t *testing.T) {
+	r := GetAsyncResult{
+		Status:     AsyncResultStatusComplete,
+		ResultType: Clone,
+		Details:    map[string]interface{}{"volumeID": float64(testVolumeId), "percentComplete": float64(50)},
+		Result:     map[string]interface{}{"cloneID": float64(7), "message": "Clone complete.", "percentComplete": float64(100)},
+	}
+	clone, err := r.AsClone()
+	require.Nil(t, err)
+	require.Equal(t, int64(7), clone.CloneID)
+	require.Equal(t, testVolumeId, clone.VolumeID)
+	require.Equal(t, int64(100), clone.PercentComplete)
+	require.Equal(t, "Clone complete.", clone.Message)
+
+	v, err := r.Decode()
+	require.Nil(t, err)
+	require.IsType(t, &CloneAsyncResult{}, v)
+}
+
+func TestGetAsyncResultDecodeErrors(t *testing.T) {
+	r := GetAsyncResult{
+		ResultType: BulkVolume,
+		Details:    map[string]interface{}{"bvID": "not a number"},
+	}
+	var decodeErr *AsyncResultDecodeError
+
+	_, err := r.AsDriveAdd()
+	require.True(t, errors.As(err, &decodeErr))
+	require.Equal(t, BulkVolume, decodeErr.ResultType)
+
+	_, err = r.AsBulkVolume()
+	require.True(t, errors.As(err, &decodeErr))
+	require.NotNil(t, decodeErr.Err)
+
+	r.ResultType = "Unregistered"
+	_, err = r.Decode()
+	require.True(t, errors.As(err, &decodeErr))
+}
+
+func TestAsyncHandleDecode(t *testing.T) {
+	h := AsyncHandle{
+		AsyncResultID: testAsyncResultId,
+		ResultType:    RtfiPendingNode,
+		Data:          map[string]interface{}{"pendingNodeID": float64(3), "activeNodeKey": "abc"},
+	}
+	node, err := h.AsRtfiPendingNode()
+	require.Nil(t, err)
+	require.Equal(t, int64(3), node.PendingNodeID)
+	require.Equal(t, "abc", node.ActiveNodeKey)
+
+	type customAsyncResult struct {
+		Widgets int64
+	}
+	RegisterAsyncResultType("CustomWidgets", func() interface{} { return &customAsyncResult{} })
+	t.Cleanup(func() { unregisterAsyncResultType("CustomWidgets") })
+	require.Panics(t, func() {
+		RegisterAsyncResultType(Clone, func() interface{} { return &customAsyncResult{} })
+	})
+	h = AsyncHandle{ResultType: "CustomWidgets", Data: map[string]interface{}{"widgets": float64(9)}}
+	v, err := h.Decode()
+	require.Nil(t, err)
+	require.Equal(t, int64(9), v.(*customAsyncResult).Widgets)
+}
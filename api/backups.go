@@ -16,6 +16,15 @@ func (c *Client) StartBulkVolumeWrite(ctx context.Context, r StartBulkVolumeWrit
 	return result, err
 }
 
+// ListBulkVolumeJobs lists the bulk volume read and write jobs on the cluster, including the
+// ones backing remote backups and restores
+func (c *Client) ListBulkVolumeJobs(ctx context.Context) (result []BulkVolumeJob, err error) {
+	lbvjr := ListBulkVolumeJobsResult{}
+	err = c.request(ctx, "ListBulkVolumeJobs", struct{}{}, &lbvjr)
+	result = lbvjr.BulkVolumeJobs
+	return result, err
+}
+
 func (c *Client) StartRemoteS3Backup(ctx context.Context, r S3BackupRequest) (result AsyncResultID, err error) {
 	bvreq := StartBulkVolumeReadRequest{
 		VolumeID:   r.VolumeID,
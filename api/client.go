@@ -2,8 +2,8 @@ package api
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -11,6 +11,8 @@ import (
 	"github.com/pkg/errors"
 )
 
+// Client is safe for concurrent use by multiple goroutines. RequestCount is used to
+// allocate JSON-RPC request ids and must only be accessed atomically.
 type Client struct {
 	Target       string
 	Port         int
@@ -19,10 +21,13 @@ type Client struct {
 	ApiUrl       string
 	Name         string
 	HTTPClient   *resty.Client
+
+	credentials  CredentialProvider
+	qosValidator *QoSValidator
 }
 
 type SFResponse struct {
-	Id     int32                  `json:"id"`
+	Id     int64                  `json:"id"`
 	Result map[string]interface{} `json:"result"`
 	Error  SFAPIError             `json:"error"`
 }
@@ -77,12 +82,26 @@ const (
 	ErrNoCredentials                   = "Client requires a valid username and password"
 	ErrInvalidCredentials              = "Provided credentials are invalid"
 	ErrUnexpectedServerError           = "Unexpected server error"
+	ErrResponseIDMismatch              = "Response id does not match request id"
+	ErrInvalidCHAPSecret               = "Invalid CHAP secret"
+	ErrSnapshotVolumeMismatch          = "Snapshot does not belong to the volume"
+	ErrActiveBulkVolumeJobs            = "Volume has active bulk volume jobs"
+	ErrInvalidSchedule                 = "Invalid schedule"
+	ErrDriveNotAvailable               = "Drive is not available"
+	ErrNoReplacementDrive              = "No available replacement drive"
+	ErrVolumeAlreadyPaired             = "Volume is already paired"
+	ErrReplicationTargetTooSmall       = "Replication target volume is smaller than the source"
+	ErrVolumeNotPaired                 = "Volume is not paired"
+	ErrReplicationPreCheckFailed       = "Replication pre-check failed"
+	ErrInvalidQoS                      = "Invalid QoS"
 	ErrVolumeIDDoesNotExist            = "xVolumeIDDoesNotExist"
 	ErrSnapshotIDDoesNotExist          = "xSnapshotIDDoesNotExist"
+	ErrGroupSnapshotIDDoesNotExist     = "xGroupSnapshotIDDoesNotExist"
 	ErrAccountIDDoesNotExist           = "xAccountIDDoesNotExist"
 	ErrQoSPolicyDoesNotExist           = "xQoSPolicyDoesNotExist"
 	ErrVolumeAccessGroupIDDoesNotExist = "xVolumeAccessGroupIdDoesNotExist"
 	ErrInitiatorDoesNotExist           = "xInitiatorDoesNotExist"
+	ErrDriveIDDoesNotExist             = "xDriveIDDoesNotExist"
 	ErrInitiatorExists                 = "xInitiatorExists"
 	ErrExceededLimit                   = "xExceededLimit"
 	ErrUnrecognizedEnumString          = "xUnrecognizedEnumString"
@@ -104,6 +123,7 @@ type ClientOptions struct {
 	Target           string
 	Username         string
 	Password         string
+	Credentials      CredentialProvider // takes precedence over Username and Password
 	Port             int
 	Version          string
 	TimeoutSecs      time.Duration
@@ -111,6 +131,22 @@ type ClientOptions struct {
 	RetryCount       int
 	RetryWaitTime    time.Duration
 	RetryMaxWaitTime time.Duration
+
+	// TLS verification. By default the cluster certificate must be signed by a CA trusted by the
+	// system or by CACertFile/CACertPEM. ServerCertFingerprint pins the hex encoded SHA-256 digest
+	// of the cluster certificate. InsecureSkipVerify disables verification entirely.
+	CACertFile            string
+	CACertPEM             []byte
+	ServerCertFingerprint string
+	ClientCertFile        string
+	ClientKeyFile         string
+	ClientCertPEM         []byte
+	ClientKeyPEM          []byte
+	InsecureSkipVerify    bool
+
+	// Check the QoS of CreateVolume, ModifyVolume and ModifyVolumes requests against the cluster
+	// limits before sending them, see QoSValidator
+	ValidateQoS bool
 }
 
 func (co *ClientOptions) validate() error {
@@ -118,8 +154,13 @@ func (co *ClientOptions) validate() error {
 	if co.Target == "" {
 		return errors.New(ErrNoTarget)
 	}
-	if co.Username == "" || co.Password == "" {
-		return errors.New(ErrNoCredentials)
+	if co.Credentials == nil {
+		if co.Username == "" || co.Password == "" {
+			return errors.New(ErrNoCredentials)
+		}
+		co.Credentials = &StaticCredentialProvider{
+			Credentials: Credentials{Username: co.Username, Password: co.Password},
+		}
 	}
 
 	// Set defaults for any unset values
@@ -150,12 +191,16 @@ func BuildClient(opts ClientOptions) (c *Client, err error) {
 		return nil, err
 	}
 
+	tlsConfig, err := buildTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build resty client instance
 	apiUrl := fmt.Sprintf("https://%s:%d/json-rpc/%s", opts.Target, opts.Port, opts.Version)
 	r := resty.New().
 		SetHeader("Accept", "application/json").
-		SetBasicAuth(opts.Username, opts.Password).
-		SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true}).
+		SetTLSClientConfig(tlsConfig).
 		SetTimeout(opts.TimeoutSecs)
 	if opts.UseRetry {
 		r = r.
@@ -167,11 +212,15 @@ func BuildClient(opts ClientOptions) (c *Client, err error) {
 
 	// Build return Client
 	SFClient := &Client{
-		Target:     opts.Target,
-		ApiUrl:     apiUrl,
-		Version:    opts.Version,
-		Port:       opts.Port,
-		HTTPClient: r,
+		Target:      opts.Target,
+		ApiUrl:      apiUrl,
+		Version:     opts.Version,
+		Port:        opts.Port,
+		HTTPClient:  r,
+		credentials: opts.Credentials,
+	}
+	if opts.ValidateQoS {
+		SFClient.qosValidator = SFClient.NewQoSValidator()
 	}
 	return SFClient, nil
 }
@@ -184,9 +233,9 @@ func BuildRequestError(name string, message string) *RequestError {
 }
 
 func requestRetryCondition(r *resty.Response, err error) bool {
-	// There was an Http error, should be retried
+	// There was an Http error, should be retried unless the cluster certificate was rejected
 	if err != nil {
-		return true
+		return !isCertificateError(err)
 	}
 	// Parse response body to check for errors.
 	_, error := processResponseErrors(r)
@@ -229,8 +278,8 @@ func processResponseErrors(resp *resty.Response) (*SFResponse, error) {
 	// Check "error" key in response JSON
 	if sfr.Error.Code != 0 {
 		switch sfr.Error.Name {
-		case ErrVolumeIDDoesNotExist, ErrSnapshotIDDoesNotExist, ErrAccountIDDoesNotExist,
-			ErrQoSPolicyDoesNotExist, ErrVolumeAccessGroupIDDoesNotExist, ErrInitiatorDoesNotExist:
+		case ErrVolumeIDDoesNotExist, ErrSnapshotIDDoesNotExist, ErrGroupSnapshotIDDoesNotExist, ErrAccountIDDoesNotExist,
+			ErrQoSPolicyDoesNotExist, ErrVolumeAccessGroupIDDoesNotExist, ErrInitiatorDoesNotExist, ErrDriveIDDoesNotExist:
 			return nil, &ResourceNotFoundError{
 				Name:    sfr.Error.Name,
 				Message: sfr.Error.Message,
@@ -250,26 +299,88 @@ func processResponseErrors(resp *resty.Response) (*SFResponse, error) {
 	return sfr, nil
 }
 
+// IsContextError reports whether err was caused by the cancellation or expiry of the
+// context.Context passed to a Client method, as opposed to an error returned by the cluster.
+func IsContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
 func (c *Client) request(ctx context.Context, method string, params interface{}, result interface{}) (err error) {
+	// Don't bother sending anything if the caller has already given up
+	if err = ctx.Err(); err != nil {
+		return errors.Wrapf(err, "%s request aborted", method)
+	}
+	creds, err := c.retrieveCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	sfr, err := c.send(ctx, method, params, creds)
+	// The credentials may have been rotated since they were last retrieved, refresh and try
+	// once more. Resending the same credentials would only risk locking out the account.
+	var reqErr *RequestError
+	if c.credentials != nil && errors.As(err, &reqErr) && reqErr.Name == ErrInvalidCredentials {
+		c.credentials.Invalidate()
+		refreshed, refreshErr := c.retrieveCredentials(ctx)
+		if refreshErr == nil && *refreshed != *creds {
+			sfr, err = c.send(ctx, method, params, refreshed)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err = mapstructure.Decode(sfr.Result, &result); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Client) retrieveCredentials(ctx context.Context) (*Credentials, error) {
+	if c.credentials == nil {
+		return nil, nil
+	}
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
+
+func (c *Client) send(ctx context.Context, method string, params interface{}, creds *Credentials) (*SFResponse, error) {
+	// AddInt64 returns the incremented value, ids start at 0
+	id := atomic.AddInt64(&c.RequestCount, 1) - 1
 	sfr := SFResponse{}
-	response, err := c.HTTPClient.R().
+	req := c.HTTPClient.R().
+		SetContext(ctx).
 		SetBody(map[string]interface{}{
-			"id":     c.RequestCount,
+			"id":     id,
 			"method": method,
 			"params": params,
 		}).
-		SetResult(&sfr).
-		Post(c.ApiUrl)
-	c.RequestCount++
+		SetResult(&sfr)
+	if creds != nil {
+		req.SetBasicAuth(creds.Username, creds.Password)
+	}
+	response, err := req.Post(c.ApiUrl)
 	if err != nil {
-		return err
+		// resty stops retrying once the context is done, but depending on where it noticed the
+		// error is either the bare context error or a transport error wrapping it. Normalize both.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, errors.Wrapf(ctxErr, "%s request aborted", method)
+		}
+		if isCertificateError(err) {
+			return nil, &CertificateVerificationError{Target: c.Target, Err: err}
+		}
+		return nil, err
 	}
 	_, err = processResponseErrors(response)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err = mapstructure.Decode(sfr.Result, &result); err != nil {
-		return err
+	if sfr.Id != id {
+		return nil, &ServiceError{
+			Name:    ErrResponseIDMismatch,
+			Message: fmt.Sprintf("%s request id %d received response id %d", method, id, sfr.Id),
+		}
 	}
-	return nil
+	return &sfr, nil
 }
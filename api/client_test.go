@@ -2,6 +2,9 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
 	"time"
 
 	"fmt"
@@ -219,3 +222,118 @@ func TestClientRequestErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestClientRequestCanceledContext(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMock(t, c, buildSFResponseWrapper(map[string]interface{}{"Volumes": []map[string]interface{}{}}))
+	defer mockReset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.ListVolumes(ctx, ListVolumesRequest{})
+	callCount := httpmock.DefaultTransport.GetTotalCallCount()
+
+	require.NotNil(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+	require.True(t, IsContextError(err))
+	var r *ServiceError
+	require.False(t, errors.As(err, &r))
+	require.Equal(t, 0, callCount)
+}
+
+func TestClientRequestDeadlineStopsRetries(t *testing.T) {
+	retryCount := 5
+	opts := ClientOptions{
+		Target:           defaultTarget,
+		Username:         defaultUsername,
+		Password:         defaultPassword,
+		TimeoutSecs:      time.Second * 10,
+		UseRetry:         true,
+		RetryCount:       retryCount,
+		RetryWaitTime:    time.Millisecond * 200,
+		RetryMaxWaitTime: time.Millisecond * 200,
+	}
+	c, err := BuildClient(opts)
+	require.Nil(t, err)
+
+	mockReset := activateMock(t, c, SFResponse{
+		Error: SFAPIError{
+			Code:    1,
+			Name:    "Unhandled service error",
+			Message: "The server encountered an unanticipated error",
+		},
+		Result: nil,
+		Id:     1,
+	})
+	defer mockReset()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	_, err = c.ListVolumes(ctx, ListVolumesRequest{})
+	callCount := httpmock.DefaultTransport.GetTotalCallCount()
+
+	require.NotNil(t, err)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	var r *ServiceError
+	require.False(t, errors.As(err, &r))
+	require.Less(t, callCount, retryCount+1)
+}
+
+func TestClientConcurrentRequests(t *testing.T) {
+	c := getTestClient(t)
+	httpmock.ActivateNonDefault(c.HTTPClient.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	var mu sync.Mutex
+	seen := map[int64]bool{}
+	httpmock.RegisterResponder("POST", c.ApiUrl, func(req *http.Request) (*http.Response, error) {
+		body := struct {
+			Id int64 `json:"id"`
+		}{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		mu.Lock()
+		seen[body.Id] = true
+		mu.Unlock()
+		resp := buildSFResponseWrapper(map[string]interface{}{"Volumes": []map[string]interface{}{testVolume}})
+		resp.Id = body.Id
+		return httpmock.NewJsonResponse(http.StatusOK, resp)
+	})
+
+	workers := 50
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.ListVolumes(context.Background(), ListVolumesRequest{})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.Nil(t, err)
+	}
+	require.Len(t, seen, workers)
+	require.Equal(t, int64(workers), c.RequestCount)
+}
+
+func TestClientResponseIdMismatch(t *testing.T) {
+	c := getTestClient(t)
+	httpmock.ActivateNonDefault(c.HTTPClient.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	resp := buildSFResponseWrapper(map[string]interface{}{"Volumes": []map[string]interface{}{testVolume}})
+	resp.Id = 42
+	responder, err := httpmock.NewJsonResponder(http.StatusOK, resp)
+	require.Nil(t, err)
+	httpmock.RegisterResponder("POST", c.ApiUrl, responder)
+
+	_, err = c.ListVolumes(context.Background(), ListVolumesRequest{})
+	require.NotNil(t, err)
+	var r *ServiceError
+	require.True(t, errors.As(err, &r))
+	require.Equal(t, ErrResponseIDMismatch, r.Name)
+}
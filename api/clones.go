@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+)
+
+const (
+	// SyncJob types
+	SyncJobTypeClone  = "clone"
+	SyncJobTypeRemote = "remote"
+	SyncJobTypeSlice  = "slice"
+	SyncJobTypeBlock  = "block"
+)
+
+// CloneVolume starts cloning a volume, or one of its snapshots, into a new volume. The new
+// volume is returned immediately but can't be used until the async handle completes.
+func (c *Client) CloneVolume(ctx context.Context, req CloneVolumeRequest) (result *CloneVolumeResult, err error) {
+	cvr := CloneVolumeResult{}
+	err = c.request(ctx, "CloneVolume", req, &cvr)
+	if err != nil {
+		return nil, err
+	}
+	result = &cvr
+	return result, err
+}
+
+// CloneMultipleVolumes clones a set of volumes as a group so all clones are crash consistent
+func (c *Client) CloneMultipleVolumes(ctx context.Context, req CloneMultipleVolumesRequest) (result *CloneMultipleVolumesResult, err error) {
+	cmvr := CloneMultipleVolumesResult{}
+	err = c.request(ctx, "CloneMultipleVolumes", req, &cmvr)
+	if err != nil {
+		return nil, err
+	}
+	result = &cmvr
+	return result, err
+}
+
+// CopyVolume overwrites the contents of an existing volume with the contents of another one
+func (c *Client) CopyVolume(ctx context.Context, req CopyVolumeRequest) (result *CopyVolumeResult, err error) {
+	cvr := CopyVolumeResult{}
+	err = c.request(ctx, "CopyVolume", req, &cvr)
+	if err != nil {
+		return nil, err
+	}
+	result = &cvr
+	return result, err
+}
+
+func (c *Client) CancelClone(ctx context.Context, cloneID int64) (err error) {
+	req := CancelCloneRequest{
+		CloneID: cloneID,
+	}
+	return c.request(ctx, "CancelClone", req, nil)
+}
+
+func (c *Client) CancelGroupClone(ctx context.Context, groupCloneID int64) (err error) {
+	req := CancelGroupCloneRequest{
+		GroupCloneID: groupCloneID,
+	}
+	return c.request(ctx, "CancelGroupClone", req, nil)
+}
+
+// ListSyncJobs lists the clone, remote replication, slice and block sync jobs running on the cluster
+func (c *Client) ListSyncJobs(ctx context.Context) (result []SyncJob, err error) {
+	lsjr := ListSyncJobsResult{}
+	err = c.request(ctx, "ListSyncJobs", struct{}{}, &lsjr)
+	result = lsjr.SyncJobs
+	return result, err
+}
+
+// ListCloneSyncJobs returns the sync jobs of a single clone or copy operation. An empty list
+// means the clone has finished or not yet started.
+func (c *Client) ListCloneSyncJobs(ctx context.Context, cloneID int64) (result []SyncJob, err error) {
+	jobs, err := c.ListSyncJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result = []SyncJob{}
+	for _, j := range jobs {
+		if j.Type == SyncJobTypeClone && j.CloneID == cloneID {
+			result = append(result, j)
+		}
+	}
+	return result, err
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testCloneId int64 = 42
+const testCloneVolumeId int64 = 3577
+
+func TestCloneVolume(t *testing.T) {
+	c := getTestClient(t)
+	clonedVolume := make(map[string]interface{})
+	for k, v := range testVolume {
+		clonedVolume[k] = v
+	}
+	clonedVolume["volumeID"] = testCloneVolumeId
+	clonedVolume["name"] = "solidfire-sdk-test-clone"
+	mockResp := buildSFResponseWrapper(map[string]interface{}{
+		"Volume":      clonedVolume,
+		"VolumeID":    testCloneVolumeId,
+		"CloneID":     testCloneId,
+		"AsyncHandle": 12,
+	})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := CloneVolumeRequest{
+		VolumeID: testVolumeId,
+		Name:     "solidfire-sdk-test-clone",
+	}
+	resp, err := c.CloneVolume(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, testCloneVolumeId, resp.VolumeID)
+	require.Equal(t, testCloneVolumeId, resp.Volume.VolumeID)
+	require.Equal(t, "solidfire-sdk-test-clone", resp.Volume.Name)
+	require.Equal(t, testCloneId, resp.CloneID)
+	require.Equal(t, AsyncResultID(12), resp.AsyncHandle)
+	require.Equal(t, "CloneVolume", (*requests)[0].Method)
+	require.Equal(t, float64(testVolumeId), (*requests)[0].Params["volumeID"])
+}
+
+func TestCloneMultipleVolumes(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{
+		"AsyncHandle":  13,
+		"GroupCloneID": 7,
+		"Members": []map[string]interface{}{
+			{"volumeID": testCloneVolumeId, "srcVolumeID": testVolumeId},
+		},
+	})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := CloneMultipleVolumesRequest{
+		Volumes: []CloneMultipleVolumeParams{{VolumeID: testVolumeId}},
+	}
+	resp, err := c.CloneMultipleVolumes(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, AsyncResultID(13), resp.AsyncHandle)
+	require.Equal(t, int64(7), resp.GroupCloneID)
+	require.Len(t, resp.Members, 1)
+	require.Equal(t, testCloneVolumeId, resp.Members[0].VolumeID)
+	require.Equal(t, testVolumeId, resp.Members[0].SrcVolumeID)
+}
+
+func TestCopyVolume(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"CloneID": testCloneId, "AsyncHandle": 14})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := CopyVolumeRequest{
+		VolumeID:    testVolumeId,
+		DstVolumeID: testCloneVolumeId,
+	}
+	resp, err := c.CopyVolume(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, testCloneId, resp.CloneID)
+	require.Equal(t, AsyncResultID(14), resp.AsyncHandle)
+}
+
+func TestCancelClone(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	require.Nil(t, c.CancelClone(ctx, testCloneId))
+	require.Nil(t, c.CancelGroupClone(ctx, 7))
+	require.Equal(t, "CancelClone", (*requests)[0].Method)
+	require.Equal(t, float64(testCloneId), (*requests)[0].Params["cloneID"])
+	require.Equal(t, "CancelGroupClone", (*requests)[1].Method)
+	require.Equal(t, float64(7), (*requests)[1].Params["groupCloneID"])
+}
+
+func TestListCloneSyncJobs(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{
+		"SyncJobs": []map[string]interface{}{
+			{"type": SyncJobTypeClone, "cloneID": testCloneId, "srcVolumeID": testVolumeId, "percentComplete": 25.0},
+			{"type": SyncJobTypeClone, "cloneID": testCloneId + 1, "srcVolumeID": testVolumeId, "percentComplete": 80.0},
+			{"type": SyncJobTypeSlice, "sliceID": 3, "percentComplete": 50.0},
+		},
+	})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	all, err := c.ListSyncJobs(ctx)
+	require.Nil(t, err)
+	require.Len(t, all, 3)
+
+	jobs, err := c.ListCloneSyncJobs(ctx, testCloneId)
+	require.Nil(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, testCloneId, jobs[0].CloneID)
+	require.Equal(t, 25.0, jobs[0].PercentComplete)
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+)
+
+const (
+	// Cluster fullness stages reported by GetClusterFullThreshold
+	FullnessStage1Happy              = "stage1Happy"
+	FullnessStage2Aware              = "stage2Aware"
+	FullnessStage3Low                = "stage3Low"
+	FullnessStage4Critical           = "stage4Critical"
+	FullnessStage5CompletelyConsumed = "stage5CompletelyConsumed"
+)
+
+// Volumes are stored in 4KiB blocks. NetApp's compression formula scales the used space by 0.93.
+const (
+	clusterBlockSize       = 4096
+	clusterCompressionBase = 0.93
+)
+
+var fullnessStages = []string{
+	FullnessStage1Happy,
+	FullnessStage2Aware,
+	FullnessStage3Low,
+	FullnessStage4Critical,
+	FullnessStage5CompletelyConsumed,
+}
+
+// FullnessStageNumber returns 1 to 5 for the given stage name, or 0 if it is unknown
+func FullnessStageNumber(stage string) int64 {
+	for i, s := range fullnessStages {
+		if s == stage {
+			return int64(i + 1)
+		}
+	}
+	return 0
+}
+
+// FullnessStageName returns the stage name for a stage number between 1 and 5
+func FullnessStageName(stage int64) string {
+	if stage < 1 || stage > int64(len(fullnessStages)) {
+		return ""
+	}
+	return fullnessStages[stage-1]
+}
+
+func ratio(numerator float64, denominator float64) float64 {
+	if denominator <= 0 {
+		return 0
+	}
+	return numerator / denominator
+}
+
+// ThinProvisioningRatio is the provisioned blocks divided by the blocks holding data. It is 0 when
+// no data has been written yet, as are the other ratios.
+func (c ClusterCapacity) ThinProvisioningRatio() float64 {
+	return ratio(float64(c.NonZeroBlocks+c.ZeroBlocks), float64(c.NonZeroBlocks))
+}
+
+// DeduplicationRatio is the blocks referenced by volumes and snapshots divided by the unique blocks stored
+func (c ClusterCapacity) DeduplicationRatio() float64 {
+	return ratio(float64(c.NonZeroBlocks+c.SnapshotNonZeroBlocks), float64(c.UniqueBlocks))
+}
+
+// CompressionRatio is the size of the unique blocks divided by the space they use on disk
+func (c ClusterCapacity) CompressionRatio() float64 {
+	return ratio(float64(c.UniqueBlocks*clusterBlockSize), float64(c.UniqueBlocksUsedSpace)*clusterCompressionBase)
+}
+
+// EfficiencyRatio combines thin provisioning, deduplication and compression
+func (c ClusterCapacity) EfficiencyRatio() float64 {
+	return c.ThinProvisioningRatio() * c.DeduplicationRatio() * c.CompressionRatio()
+}
+
+// BlockStage returns the block fullness stage, 1 to 5, reported by the cluster, or 0 if it is unknown
+func (t GetClusterFullThresholdResult) BlockStage() int64 {
+	return FullnessStageNumber(t.BlockFullness)
+}
+
+// ClusterCapacityReport summarizes the efficiency and fullness of a cluster
+type ClusterCapacityReport struct {
+	Capacity  ClusterCapacity
+	Threshold GetClusterFullThresholdResult
+
+	ThinProvisioning float64
+	Deduplication    float64
+	Compression      float64
+	Efficiency       float64
+
+	// Percentage of the block capacity in use
+	UsedPercent float64
+	// Worst of the block and metadata fullness stages, 1 to 5
+	Stage     int64
+	StageName string
+	// Bytes that can be written before the block fullness reaches the next stage, 0 in stage 5
+	BytesUntilNextStage int64
+}
+
+func (c *Client) GetClusterInfo(ctx context.Context) (result *ClusterInfo, err error) {
+	gcir := GetClusterInfoResult{}
+	err = c.request(ctx, "GetClusterInfo", struct{}{}, &gcir)
+	if err != nil {
+		return nil, err
+	}
+	result = &gcir.ClusterInfo
+	return result, err
+}
+
+func (c *Client) GetClusterCapacity(ctx context.Context) (result *ClusterCapacity, err error) {
+	gccr := GetClusterCapacityResult{}
+	err = c.request(ctx, "GetClusterCapacity", struct{}{}, &gccr)
+	if err != nil {
+		return nil, err
+	}
+	result = &gccr.ClusterCapacity
+	return result, err
+}
+
+func (c *Client) GetClusterStats(ctx context.Context) (result *ClusterStats, err error) {
+	gcsr := GetClusterStatsResult{}
+	err = c.request(ctx, "GetClusterStats", struct{}{}, &gcsr)
+	if err != nil {
+		return nil, err
+	}
+	result = &gcsr.ClusterStats
+	return result, err
+}
+
+func (c *Client) GetClusterVersionInfo(ctx context.Context) (result *GetClusterVersionInfoResult, err error) {
+	gcvir := GetClusterVersionInfoResult{}
+	err = c.request(ctx, "GetClusterVersionInfo", struct{}{}, &gcvir)
+	if err != nil {
+		return nil, err
+	}
+	result = &gcvir
+	return result, err
+}
+
+// GetClusterState returns the cluster membership of the node serving the request, or of every
+// node when force is true
+func (c *Client) GetClusterState(ctx context.Context, force bool) (result *GetClusterStateResult, err error) {
+	req := GetClusterStateRequest{
+		Force: force,
+	}
+	gcsr := GetClusterStateResult{}
+	err = c.request(ctx, "GetClusterState", req, &gcsr)
+	if err != nil {
+		return nil, err
+	}
+	result = &gcsr
+	return result, err
+}
+
+func (c *Client) GetClusterFullThreshold(ctx context.Context) (result *GetClusterFullThresholdResult, err error) {
+	gcftr := GetClusterFullThresholdResult{}
+	err = c.request(ctx, "GetClusterFullThreshold", struct{}{}, &gcftr)
+	if err != nil {
+		return nil, err
+	}
+	result = &gcftr
+	return result, err
+}
+
+func (c *Client) GetLimits(ctx context.Context) (result *GetLimitsResult, err error) {
+	glr := GetLimitsResult{}
+	err = c.request(ctx, "GetLimits", struct{}{}, &glr)
+	if err != nil {
+		return nil, err
+	}
+	result = &glr
+	return result, err
+}
+
+// GetClusterCapacityReport combines GetClusterCapacity and GetClusterFullThreshold
+func (c *Client) GetClusterCapacityReport(ctx context.Context) (result *ClusterCapacityReport, err error) {
+	capacity, err := c.GetClusterCapacity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	threshold, err := c.GetClusterFullThreshold(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result = &ClusterCapacityReport{
+		Capacity:         *capacity,
+		Threshold:        *threshold,
+		ThinProvisioning: capacity.ThinProvisioningRatio(),
+		Deduplication:    capacity.DeduplicationRatio(),
+		Compression:      capacity.CompressionRatio(),
+		Efficiency:       capacity.EfficiencyRatio(),
+		UsedPercent:      ratio(float64(threshold.SumUsedClusterBytes)*100, float64(threshold.SumTotalClusterBytes)),
+	}
+
+	blockStage := threshold.BlockStage()
+	result.Stage = FullnessStageNumber(threshold.Fullness)
+	for _, stage := range []int64{blockStage, FullnessStageNumber(threshold.MetadataFullness)} {
+		if stage > result.Stage {
+			result.Stage = stage
+		}
+	}
+	result.StageName = FullnessStageName(result.Stage)
+
+	next := []int64{
+		threshold.Stage2BlockThresholdBytes,
+		threshold.Stage3BlockThresholdBytes,
+		threshold.Stage4BlockThresholdBytes,
+		threshold.Stage5BlockThresholdBytes,
+	}
+	if blockStage >= 1 && blockStage < 5 && next[blockStage-1] > threshold.SumUsedClusterBytes {
+		result.BytesUntilNextStage = next[blockStage-1] - threshold.SumUsedClusterBytes
+	}
+	return result, nil
+}
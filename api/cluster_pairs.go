@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Valid cluster pair status values
+	ClusterPairStatusConnected     = "Connected"
+	ClusterPairStatusMisconfigured = "Misconfigured"
+	ClusterPairStatusDisconnected  = "Disconnected"
+)
+
+// StartClusterPairing creates a cluster pair on the local cluster and returns the key to pass to
+// CompleteClusterPairing on the remote cluster
+func (c *Client) StartClusterPairing(ctx context.Context) (result *StartClusterPairingResult, err error) {
+	scpr := StartClusterPairingResult{}
+	err = c.request(ctx, "StartClusterPairing", struct{}{}, &scpr)
+	if err != nil {
+		return nil, err
+	}
+	result = &scpr
+	return result, err
+}
+
+// CompleteClusterPairing completes a pairing started on another cluster and returns the ID of the
+// pair on this cluster
+func (c *Client) CompleteClusterPairing(ctx context.Context, clusterPairingKey string) (clusterPairID int64, err error) {
+	req := CompleteClusterPairingRequest{
+		ClusterPairingKey: clusterPairingKey,
+	}
+	ccpr := CompleteClusterPairingResult{}
+	err = c.request(ctx, "CompleteClusterPairing", req, &ccpr)
+	return ccpr.ClusterPairID, err
+}
+
+func (c *Client) ListClusterPairs(ctx context.Context) (result []PairedCluster, err error) {
+	lcpr := ListClusterPairsResult{}
+	err = c.request(ctx, "ListClusterPairs", struct{}{}, &lcpr)
+	result = lcpr.ClusterPairs
+	return result, err
+}
+
+// RemoveClusterPair removes the pair on this cluster only, it must be removed from the remote
+// cluster as well
+func (c *Client) RemoveClusterPair(ctx context.Context, clusterPairID int64) (err error) {
+	req := RemoveClusterPairRequest{
+		ClusterPairID: clusterPairID,
+	}
+	return c.request(ctx, "RemoveClusterPair", req, nil)
+}
+
+type ClusterPairing struct {
+	LocalClusterPairID  int64
+	RemoteClusterPairID int64
+}
+
+// PairClusters pairs the clusters of the two clients. If the remote cluster rejects the pairing key
+// the half finished pair is removed from the local cluster.
+func PairClusters(ctx context.Context, local *Client, remote *Client) (result *ClusterPairing, err error) {
+	started, err := local.StartClusterPairing(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "starting cluster pairing on %s", local.Target)
+	}
+	remoteID, err := remote.CompleteClusterPairing(ctx, started.ClusterPairingKey)
+	if err != nil {
+		err = errors.Wrapf(err, "completing cluster pairing on %s", remote.Target)
+		if removeErr := local.RemoveClusterPair(ctx, started.ClusterPairID); removeErr != nil {
+			err = errors.Wrapf(err, "cluster pair %d left on %s: %v", started.ClusterPairID, local.Target, removeErr)
+		}
+		return nil, err
+	}
+	result = &ClusterPairing{
+		LocalClusterPairID:  started.ClusterPairID,
+		RemoteClusterPairID: remoteID,
+	}
+	return result, nil
+}
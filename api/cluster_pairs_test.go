@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testClusterPairId int64 = 1
+const testRemoteClusterPairId int64 = 2
+const testClusterPairingKey = "7b22636c7573746572506169724944223a317d"
+
+func TestStartClusterPairing(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{
+		"ClusterPairingKey": testClusterPairingKey,
+		"ClusterPairID":     testClusterPairId,
+	})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.StartClusterPairing(ctx)
+	require.Nil(t, err)
+	require.Equal(t, testClusterPairingKey, resp.ClusterPairingKey)
+	require.Equal(t, testClusterPairId, resp.ClusterPairID)
+}
+
+func TestCompleteClusterPairing(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"ClusterPairID": testRemoteClusterPairId})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.CompleteClusterPairing(ctx, testClusterPairingKey)
+	require.Nil(t, err)
+	require.Equal(t, testRemoteClusterPairId, resp)
+	require.Equal(t, testClusterPairingKey, (*requests)[0].Params["clusterPairingKey"])
+}
+
+func TestListClusterPairs(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"ClusterPairs": []map[string]interface{}{
+		{
+			"clusterName":     "solidfire-sdk-remote",
+			"clusterPairID":   testClusterPairId,
+			"clusterPairUUID": "7e5b5ed5-54e1-4b27-8d2b-66e0b3ea3f3e",
+			"latency":         1,
+			"mvip":            "10.0.10.1",
+			"status":          ClusterPairStatusConnected,
+			"version":         "12.3.0.958",
+		},
+	}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ListClusterPairs(ctx)
+	require.Nil(t, err)
+	require.Len(t, resp, 1)
+	require.Equal(t, "10.0.10.1", resp[0].Mvip)
+	require.Equal(t, ClusterPairStatusConnected, resp[0].Status)
+}
+
+func TestRemoveClusterPair(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, buildSFResponseWrapper(map[string]interface{}{}))
+	defer mockReset()
+
+	ctx := context.Background()
+	err := c.RemoveClusterPair(ctx, testClusterPairId)
+	require.Nil(t, err)
+	require.Equal(t, float64(testClusterPairId), (*requests)[0].Params["clusterPairID"])
+}
+
+func TestPairClusters(t *testing.T) {
+	local := getTestClient(t)
+	remote := getTestRemoteClient(t)
+	localRequests, mockReset := activateRecordingMock(local, buildSFResponseWrapper(map[string]interface{}{
+		"ClusterPairingKey": testClusterPairingKey,
+		"ClusterPairID":     testClusterPairId,
+	}))
+	defer mockReset()
+	remoteRequests, _ := activateRecordingMock(remote, buildSFResponseWrapper(map[string]interface{}{
+		"ClusterPairID": testRemoteClusterPairId,
+	}))
+
+	ctx := context.Background()
+	resp, err := PairClusters(ctx, local, remote)
+	require.Nil(t, err)
+	require.Equal(t, testClusterPairId, resp.LocalClusterPairID)
+	require.Equal(t, testRemoteClusterPairId, resp.RemoteClusterPairID)
+	require.Len(t, *localRequests, 1)
+	require.Len(t, *remoteRequests, 1)
+	require.Equal(t, testClusterPairingKey, (*remoteRequests)[0].Params["clusterPairingKey"])
+}
+
+func TestPairClustersRollback(t *testing.T) {
+	local := getTestClient(t)
+	remote := getTestRemoteClient(t)
+	localRequests, mockReset := activateRecordingMock(local,
+		buildSFResponseWrapper(map[string]interface{}{
+			"ClusterPairingKey": testClusterPairingKey,
+			"ClusterPairID":     testClusterPairId,
+		}),
+		buildSFResponseWrapper(map[string]interface{}{}),
+	)
+	defer mockReset()
+	_, _ = activateRecordingMock(remote, SFResponse{
+		Error: SFAPIError{
+			Code:    500,
+			Name:    ErrInvalidParameter,
+			Message: "Invalid cluster pairing key",
+		},
+	})
+
+	ctx := context.Background()
+	_, err := PairClusters(ctx, local, remote)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "remotehost")
+	require.Len(t, *localRequests, 2)
+	require.Equal(t, "RemoveClusterPair", (*localRequests)[1].Method)
+	require.Equal(t, float64(testClusterPairId), (*localRequests)[1].Params["clusterPairID"])
+}
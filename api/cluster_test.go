@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testClusterCapacity = map[string]interface{}{
+	"activeBlockSpace":      int64(2000000000),
+	"activeSessions":        10,
+	"maxUsedSpace":          int64(100000000000),
+	"nonZeroBlocks":         1000,
+	"snapshotNonZeroBlocks": 500,
+	"uniqueBlocks":          500,
+	"uniqueBlocksUsedSpace": 1000000,
+	"usedSpace":             int64(40000000000),
+	"zeroBlocks":            3000,
+	"timestamp":             "2021-05-24T15:18:14Z",
+}
+
+var testClusterFullThreshold = map[string]interface{}{
+	"blockFullness":             FullnessStage2Aware,
+	"fullness":                  FullnessStage2Aware,
+	"metadataFullness":          FullnessStage1Happy,
+	"stage2BlockThresholdBytes": int64(30000000000),
+	"stage3BlockThresholdBytes": int64(60000000000),
+	"stage4BlockThresholdBytes": int64(80000000000),
+	"stage5BlockThresholdBytes": int64(100000000000),
+	"sumTotalClusterBytes":      int64(100000000000),
+	"sumUsedClusterBytes":       int64(40000000000),
+}
+
+func TestClusterCapacityRatios(t *testing.T) {
+	capacity := ClusterCapacity{
+		NonZeroBlocks:         1000,
+		SnapshotNonZeroBlocks: 500,
+		UniqueBlocks:          500,
+		UniqueBlocksUsedSpace: 1000000,
+		ZeroBlocks:            3000,
+	}
+	require.Equal(t, 4.0, capacity.ThinProvisioningRatio())
+	require.Equal(t, 3.0, capacity.DeduplicationRatio())
+	require.InDelta(t, 2.2022, capacity.CompressionRatio(), 0.0001)
+	require.InDelta(t, 4.0*3.0*2.2022, capacity.EfficiencyRatio(), 0.001)
+
+	empty := ClusterCapacity{}
+	require.Equal(t, 0.0, empty.ThinProvisioningRatio())
+	require.Equal(t, 0.0, empty.DeduplicationRatio())
+	require.Equal(t, 0.0, empty.CompressionRatio())
+}
+
+func TestFullnessStages(t *testing.T) {
+	require.Equal(t, int64(1), FullnessStageNumber(FullnessStage1Happy))
+	require.Equal(t, int64(5), FullnessStageNumber(FullnessStage5CompletelyConsumed))
+	require.Equal(t, int64(0), FullnessStageNumber("unknown"))
+	require.Equal(t, FullnessStage4Critical, FullnessStageName(4))
+	require.Equal(t, "", FullnessStageName(6))
+
+	// The reported stage wins over the used bytes, the cluster applies hysteresis between stages
+	threshold := GetClusterFullThresholdResult{
+		BlockFullness:             FullnessStage3Low,
+		Stage2BlockThresholdBytes: 30,
+		Stage3BlockThresholdBytes: 60,
+		SumUsedClusterBytes:       59,
+	}
+	require.Equal(t, int64(3), threshold.BlockStage())
+	require.Equal(t, int64(0), GetClusterFullThresholdResult{SumUsedClusterBytes: 59}.BlockStage())
+}
+
+func TestGetClusterInfo(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"ClusterInfo": map[string]interface{}{
+		"name":     "solidfire-sdk-test",
+		"mvip":     "10.0.0.1",
+		"svip":     "10.0.1.1",
+		"repCount": 2,
+		"ensemble": []string{"10.0.2.1", "10.0.2.2", "10.0.2.3"},
+		"uniqueID": "abcd",
+	}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetClusterInfo(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "solidfire-sdk-test", resp.Name)
+	require.Equal(t, "10.0.0.1", resp.Mvip)
+	require.Equal(t, int64(2), resp.RepCount)
+	require.Len(t, resp.Ensemble, 3)
+}
+
+func TestGetClusterStats(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"ClusterStats": map[string]interface{}{
+		"clusterUtilization": 0.25,
+		"readOps":            100,
+		"writeOps":           200,
+	}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetClusterStats(ctx)
+	require.Nil(t, err)
+	require.Equal(t, 0.25, resp.ClusterUtilization)
+	require.Equal(t, int64(200), resp.WriteOps)
+}
+
+func TestGetClusterVersionInfo(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{
+		"ClusterAPIVersion": "12.3",
+		"ClusterVersion":    "12.3.0.958",
+		"ClusterVersionInfo": []map[string]interface{}{
+			{"nodeID": 1, "nodeVersion": "12.3.0.958"},
+		},
+	})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetClusterVersionInfo(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "12.3", resp.ClusterAPIVersion)
+	require.Equal(t, "12.3.0.958", resp.ClusterVersionInfo[0].NodeVersion)
+}
+
+func TestGetClusterState(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"Cluster": "solidfire-sdk-test", "State": "Active"})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetClusterState(ctx, false)
+	require.Nil(t, err)
+	require.Equal(t, "Active", resp.State)
+	require.Equal(t, false, (*requests)[0].Params["force"])
+}
+
+func TestGetLimits(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{
+		"VolumeMinIOPSMin": 50,
+		"VolumeMaxIOPSMax": 200000,
+		"VolumeSizeMax":    int64(17592186044416),
+	})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetLimits(ctx)
+	require.Nil(t, err)
+	require.Equal(t, int64(50), resp.VolumeMinIOPSMin)
+	require.Equal(t, int64(200000), resp.VolumeMaxIOPSMax)
+	require.Equal(t, int64(17592186044416), resp.VolumeSizeMax)
+}
+
+func TestGetClusterCapacityReport(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildSFResponseWrapper(map[string]interface{}{"ClusterCapacity": testClusterCapacity}),
+		buildSFResponseWrapper(testClusterFullThreshold),
+	)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetClusterCapacityReport(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "GetClusterCapacity", (*requests)[0].Method)
+	require.Equal(t, "GetClusterFullThreshold", (*requests)[1].Method)
+	require.Equal(t, 4.0, resp.ThinProvisioning)
+	require.Equal(t, 3.0, resp.Deduplication)
+	require.Equal(t, 40.0, resp.UsedPercent)
+	require.Equal(t, int64(2), resp.Stage)
+	require.Equal(t, FullnessStage2Aware, resp.StageName)
+	require.Equal(t, int64(20000000000), resp.BytesUntilNextStage)
+}
+
+func TestGetClusterCapacityReportReportedStage(t *testing.T) {
+	c := getTestClient(t)
+	threshold := map[string]interface{}{}
+	for k, v := range testClusterFullThreshold {
+		threshold[k] = v
+	}
+	// Still in stage 3 after dropping below its threshold
+	threshold["blockFullness"] = FullnessStage3Low
+	threshold["fullness"] = FullnessStage3Low
+	mockReset := activateMockSequence(c,
+		buildSFResponseWrapper(map[string]interface{}{"ClusterCapacity": testClusterCapacity}),
+		buildSFResponseWrapper(threshold),
+	)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetClusterCapacityReport(ctx)
+	require.Nil(t, err)
+	require.Equal(t, int64(3), resp.Stage)
+	require.Equal(t, FullnessStage3Low, resp.StageName)
+	require.Equal(t, int64(40000000000), resp.BytesUntilNextStage)
+}
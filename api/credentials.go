@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Environment variables read by EnvCredentialProvider by default
+	DefaultUsernameEnvVar = "SOLIDFIRE_USER"
+	DefaultPasswordEnvVar = "SOLIDFIRE_PASS"
+)
+
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (c Credentials) validate() error {
+	if c.Username == "" || c.Password == "" {
+		return errors.New(ErrNoCredentials)
+	}
+	return nil
+}
+
+// CredentialProvider supplies the cluster admin credentials used by a Client. Retrieve is called
+// before every request so implementations should be cheap and must be safe for concurrent use.
+//
+// When the cluster rejects a request with ErrInvalidCredentials the Client calls Invalidate,
+// retrieves the credentials again and, if they changed, retries the request once.
+type CredentialProvider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+	Invalidate()
+}
+
+// StaticCredentialProvider always returns the same credentials
+type StaticCredentialProvider struct {
+	Credentials Credentials
+}
+
+func (p *StaticCredentialProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	return p.Credentials, p.Credentials.validate()
+}
+
+func (p *StaticCredentialProvider) Invalidate() {}
+
+// EnvCredentialProvider reads the credentials from environment variables on every request,
+// defaulting to $SOLIDFIRE_USER and $SOLIDFIRE_PASS
+type EnvCredentialProvider struct {
+	UsernameVar string
+	PasswordVar string
+}
+
+func (p *EnvCredentialProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	usernameVar, passwordVar := p.UsernameVar, p.PasswordVar
+	if usernameVar == "" {
+		usernameVar = DefaultUsernameEnvVar
+	}
+	if passwordVar == "" {
+		passwordVar = DefaultPasswordEnvVar
+	}
+	creds := Credentials{
+		Username: os.Getenv(usernameVar),
+		Password: os.Getenv(passwordVar),
+	}
+	if err := creds.validate(); err != nil {
+		return creds, errors.Wrapf(err, "reading $%s and $%s", usernameVar, passwordVar)
+	}
+	return creds, nil
+}
+
+func (p *EnvCredentialProvider) Invalidate() {}
+
+// FileCredentialProvider reads the credentials from a JSON file of the form
+// {"username": "...", "password": "..."}. The file is read again whenever its modification time
+// or size changes, so a rotated password is picked up without restarting the process.
+type FileCredentialProvider struct {
+	Path string
+
+	mu      sync.Mutex
+	creds   Credentials
+	modTime time.Time
+	size    int64
+	loaded  bool
+}
+
+func NewFileCredentialProvider(path string) *FileCredentialProvider {
+	return &FileCredentialProvider{Path: path}
+}
+
+func (p *FileCredentialProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.Path)
+	if err != nil {
+		return Credentials{}, errors.Wrap(err, "reading credentials file")
+	}
+	if p.loaded && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return p.creds, nil
+	}
+
+	data, err := ioutil.ReadFile(p.Path)
+	if err != nil {
+		return Credentials{}, errors.Wrap(err, "reading credentials file")
+	}
+	creds := Credentials{}
+	if err = json.Unmarshal(data, &creds); err != nil {
+		return Credentials{}, errors.Wrapf(err, "parsing credentials file %s", p.Path)
+	}
+	if err = creds.validate(); err != nil {
+		return Credentials{}, errors.Wrapf(err, "credentials file %s", p.Path)
+	}
+	p.creds = creds
+	p.modTime = info.ModTime()
+	p.size = info.Size()
+	p.loaded = true
+	return creds, nil
+}
+
+// Invalidate forces the file to be read again on the next call to Retrieve
+func (p *FileCredentialProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.loaded = false
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const rotatedPassword = "rotated-supersecret"
+
+// rotatingCredentialProvider returns the original password until it is invalidated
+type rotatingCredentialProvider struct {
+	mu          sync.Mutex
+	rotated     bool
+	invalidated int
+}
+
+func (p *rotatingCredentialProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rotated {
+		return Credentials{Username: defaultUsername, Password: rotatedPassword}, nil
+	}
+	return Credentials{Username: defaultUsername, Password: defaultPassword}, nil
+}
+
+func (p *rotatingCredentialProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rotated = true
+	p.invalidated++
+}
+
+// activateAuthMock only accepts requests made with the rotated password
+func activateAuthMock(c *Client) (mockReset func()) {
+	httpmock.ActivateNonDefault(c.HTTPClient.GetClient())
+	httpmock.RegisterResponder("POST", c.ApiUrl, func(req *http.Request) (*http.Response, error) {
+		username, password, ok := req.BasicAuth()
+		if !ok || username != defaultUsername || password != rotatedPassword {
+			return httpmock.NewStringResponse(http.StatusUnauthorized, "unauthorized"), nil
+		}
+		body := struct {
+			Id int64 `json:"id"`
+		}{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		resp := buildSFResponseWrapper(map[string]interface{}{"Volumes": []map[string]interface{}{testVolume}})
+		resp.Id = body.Id
+		return httpmock.NewJsonResponse(http.StatusOK, resp)
+	})
+	return httpmock.DeactivateAndReset
+}
+
+func TestClientRefreshesRotatedCredentials(t *testing.T) {
+	provider := &rotatingCredentialProvider{}
+	c, err := BuildClient(ClientOptions{
+		Target:      defaultTarget,
+		Credentials: provider,
+	})
+	require.Nil(t, err)
+	mockReset := activateAuthMock(c)
+	defer mockReset()
+
+	resp, err := c.ListVolumes(context.Background(), ListVolumesRequest{})
+	require.Nil(t, err)
+	require.Equal(t, testVolumeId, resp[0].VolumeID)
+	require.Equal(t, 1, provider.invalidated)
+	require.Equal(t, 2, httpmock.GetTotalCallCount())
+
+	// Subsequent requests use the refreshed credentials straight away
+	_, err = c.ListVolumes(context.Background(), ListVolumesRequest{})
+	require.Nil(t, err)
+	require.Equal(t, 1, provider.invalidated)
+	require.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestClientDoesNotResendUnchangedCredentials(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateAuthMock(c)
+	defer mockReset()
+
+	_, err := c.ListVolumes(context.Background(), ListVolumesRequest{})
+	require.NotNil(t, err)
+	var r *RequestError
+	require.True(t, errors.As(err, &r))
+	require.Equal(t, ErrInvalidCredentials, r.Name)
+	require.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestFileCredentialProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "solidfire-sdk-credentials")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "credentials.json")
+	ctx := context.Background()
+
+	p := NewFileCredentialProvider(path)
+	_, err = p.Retrieve(ctx)
+	require.NotNil(t, err)
+
+	require.Nil(t, ioutil.WriteFile(path, []byte(`{"username": "admin", "password": "first"}`), 0600))
+	creds, err := p.Retrieve(ctx)
+	require.Nil(t, err)
+	require.Equal(t, Credentials{Username: "admin", Password: "first"}, creds)
+
+	require.Nil(t, ioutil.WriteFile(path, []byte(`{"username": "admin", "password": "second"}`), 0600))
+	future := time.Now().Add(time.Hour)
+	require.Nil(t, os.Chtimes(path, future, future))
+	creds, err = p.Retrieve(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "second", creds.Password)
+
+	require.Nil(t, ioutil.WriteFile(path, []byte(`{"username": "admin"}`), 0600))
+	p.Invalidate()
+	_, err = p.Retrieve(ctx)
+	require.NotNil(t, err)
+}
+
+func TestEnvCredentialProvider(t *testing.T) {
+	p := &EnvCredentialProvider{UsernameVar: "SOLIDFIRE_SDK_TEST_USER", PasswordVar: "SOLIDFIRE_SDK_TEST_PASS"}
+	ctx := context.Background()
+	_, err := p.Retrieve(ctx)
+	require.NotNil(t, err)
+
+	os.Setenv("SOLIDFIRE_SDK_TEST_USER", defaultUsername)
+	os.Setenv("SOLIDFIRE_SDK_TEST_PASS", defaultPassword)
+	defer os.Unsetenv("SOLIDFIRE_SDK_TEST_USER")
+	defer os.Unsetenv("SOLIDFIRE_SDK_TEST_PASS")
+	creds, err := p.Retrieve(ctx)
+	require.Nil(t, err)
+	require.Equal(t, Credentials{Username: defaultUsername, Password: defaultPassword}, creds)
+}
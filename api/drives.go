@@ -0,0 +1,243 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	// Valid drive status values
+	DriveStatusAvailable = "available"
+	DriveStatusActive    = "active"
+	DriveStatusErasing   = "erasing"
+	DriveStatusFailed    = "failed"
+	DriveStatusRemoving  = "removing"
+)
+
+func (c *Client) ListDrives(ctx context.Context) (result []DriveInfo, err error) {
+	ldr := ListDrivesResult{}
+	err = c.request(ctx, "ListDrives", struct{}{}, &ldr)
+	result = ldr.Drives
+	return result, err
+}
+
+func (c *Client) GetDriveById(ctx context.Context, id int64) (result *DriveInfo, err error) {
+	drives, err := c.ListDrives(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range drives {
+		if drives[i].DriveID == id {
+			return &drives[i], nil
+		}
+	}
+	return nil, BuildRequestError(ErrDriveIDDoesNotExist, fmt.Sprintf("Drive with the given id %d does not exist", id))
+}
+
+// AddDrives adds available drives to the cluster. The returned handle completes once the
+// cluster has finished syncing data onto the new drives.
+func (c *Client) AddDrives(ctx context.Context, req AddDrivesRequest) (result AsyncResultID, err error) {
+	adr := AddDrivesResult{}
+	err = c.request(ctx, "AddDrives", req, &adr)
+	return adr.AsyncHandle, err
+}
+
+// RemoveDrives removes drives from the cluster. The returned handle completes once the data on
+// the drives has been moved to the rest of the cluster.
+func (c *Client) RemoveDrives(ctx context.Context, req RemoveDrivesRequest) (result AsyncResultID, err error) {
+	ahr := AsyncHandleResult{}
+	err = c.request(ctx, "RemoveDrives", req, &ahr)
+	return ahr.AsyncHandle, err
+}
+
+func (c *Client) GetDriveStats(ctx context.Context, id int64) (result *DriveStats, err error) {
+	req := GetDriveStatsRequest{
+		DriveID: id,
+	}
+	gdsr := GetDriveStatsResult{}
+	err = c.request(ctx, "GetDriveStats", req, &gdsr)
+	if err != nil {
+		return nil, err
+	}
+	result = &gdsr.DriveStats
+	return result, err
+}
+
+// ListDriveStats returns the stats of the given drives, or of all drives when ids is empty
+func (c *Client) ListDriveStats(ctx context.Context, ids []int64) (result []DriveStats, err error) {
+	req := ListDriveStatsRequest{
+		Drives: ids,
+	}
+	ldsr := ListDriveStatsResult{}
+	err = c.request(ctx, "ListDriveStats", req, &ldsr)
+	result = ldsr.DriveStats
+	return result, err
+}
+
+func (c *Client) GetDriveHardwareInfo(ctx context.Context, id int64) (result *DriveHardwareInfo, err error) {
+	req := GetDriveHardwareInfoRequest{
+		DriveID: id,
+	}
+	gdhir := GetDriveHardwareInfoResult{}
+	err = c.request(ctx, "GetDriveHardwareInfo", req, &gdhir)
+	if err != nil {
+		return nil, err
+	}
+	result = &gdhir.DriveHardwareInfo
+	return result, err
+}
+
+// ListDriveHardware returns the hardware details of the drives of every node
+func (c *Client) ListDriveHardware(ctx context.Context, force bool) (result []NodeDriveHardware, err error) {
+	req := ListDriveHardwareRequest{
+		Force: force,
+	}
+	ldhr := ListDriveHardwareResult{}
+	err = c.request(ctx, "ListDriveHardware", req, &ldhr)
+	result = ldhr.Nodes
+	return result, err
+}
+
+// SecureEraseDrives erases available drives. The drives are in the erasing state until the
+// returned handle completes.
+func (c *Client) SecureEraseDrives(ctx context.Context, ids []int64) (result AsyncResultID, err error) {
+	req := SecureEraseDrivesRequest{
+		Drives: ids,
+	}
+	ahr := AsyncHandleResult{}
+	err = c.request(ctx, "SecureEraseDrives", req, &ahr)
+	return ahr.AsyncHandle, err
+}
+
+// ResetDrives initializes drives and removes all data on them. It must be called on a client
+// connected to the node the drives belong to. ids are device names such as /dev/slot0.
+func (c *Client) ResetDrives(ctx context.Context, ids []string, force bool) (result *ResetDrivesDetails, err error) {
+	req := ResetDrivesRequest{
+		Drives: strings.Join(ids, ","),
+		Force:  force,
+	}
+	rdr := ResetDrivesResult{}
+	err = c.request(ctx, "ResetDrives", req, &rdr)
+	if err != nil {
+		return nil, err
+	}
+	result = &rdr.Details
+	return result, err
+}
+
+// TestDrives runs a hardware validation on all the drives of a node that is not part of a
+// cluster. It must be called on a client connected to that node.
+func (c *Client) TestDrives(ctx context.Context, minutes int64) (result *TestDrivesResult, err error) {
+	req := TestDrivesRequest{
+		Minutes: minutes,
+	}
+	tdr := TestDrivesResult{}
+	err = c.request(ctx, "TestDrives", req, &tdr)
+	if err != nil {
+		return nil, err
+	}
+	result = &tdr
+	return result, err
+}
+
+type DriveReplacementRequest struct {
+	FailedDriveID int64
+	// Drive to add in place of the failed one. When 0 the available drive in the same node and
+	// slot as the failed drive is used.
+	ReplacementDriveID int64
+	// Options used while waiting for the failed drive to be removed
+	WaitOptions AsyncWaitOptions
+}
+
+type DriveReplacementResult struct {
+	RemovedDriveID int64
+	AddedDriveID   int64
+	// Handle of the removal, 0 when the failed drive had already been removed
+	RemovalHandle AsyncResultID
+	// Handle of the sync onto the replacement drive
+	AddHandle AsyncResultID
+}
+
+// ReplaceDrive removes a failed drive, waits for its data to be moved to the rest of the cluster,
+// then adds the replacement drive. Waiting for the data to be synced onto the replacement is left
+// to the caller through AddHandle.
+func (c *Client) ReplaceDrive(ctx context.Context, req DriveReplacementRequest) (result *DriveReplacementResult, err error) {
+	failed, err := c.GetDriveById(ctx, req.FailedDriveID)
+	if err != nil {
+		return nil, err
+	}
+	result = &DriveReplacementResult{
+		RemovedDriveID: failed.DriveID,
+	}
+
+	switch failed.Status {
+	case DriveStatusActive, DriveStatusFailed:
+		result.RemovalHandle, err = c.RemoveDrives(ctx, RemoveDrivesRequest{Drives: []int64{failed.DriveID}})
+	case DriveStatusRemoving:
+		result.RemovalHandle, err = c.findDriveRemoval(ctx, failed.DriveID)
+	}
+	if err != nil {
+		return result, err
+	}
+	if result.RemovalHandle != 0 {
+		if _, err = c.WaitForAsyncResult(ctx, result.RemovalHandle, req.WaitOptions, nil); err != nil {
+			return result, err
+		}
+	}
+
+	drives, err := c.ListDrives(ctx)
+	if err != nil {
+		return result, err
+	}
+	var replacement *DriveInfo
+	for i, d := range drives {
+		if d.Status != DriveStatusAvailable || d.DriveID == failed.DriveID {
+			continue
+		}
+		if req.ReplacementDriveID != 0 {
+			if d.DriveID == req.ReplacementDriveID {
+				replacement = &drives[i]
+				break
+			}
+		} else if d.NodeID == failed.NodeID && d.Slot == failed.Slot {
+			replacement = &drives[i]
+			break
+		}
+	}
+	if replacement == nil {
+		if req.ReplacementDriveID != 0 {
+			return result, BuildRequestError(ErrDriveNotAvailable,
+				fmt.Sprintf("Drive %d is not available to replace drive %d", req.ReplacementDriveID, failed.DriveID))
+		}
+		return result, BuildRequestError(ErrNoReplacementDrive,
+			fmt.Sprintf("No available drive in node %d slot %d to replace drive %d", failed.NodeID, failed.Slot, failed.DriveID))
+	}
+
+	result.AddedDriveID = replacement.DriveID
+	result.AddHandle, err = c.AddDrives(ctx, AddDrivesRequest{Drives: []NewDrive{{DriveID: replacement.DriveID}}})
+	return result, err
+}
+
+// findDriveRemoval returns the handle of the pending DriveRemoval of the given drive, or 0 if there is none
+func (c *Client) findDriveRemoval(ctx context.Context, id int64) (result AsyncResultID, err error) {
+	results, err := c.ListAllAsyncTasks(ctx, ListAsyncResultsRequest{AsyncResultTypes: []string{DriveRemoval}})
+	if err != nil {
+		return 0, err
+	}
+	for _, h := range results.AsyncHandles {
+		if h.Completed {
+			continue
+		}
+		removal, err := h.AsDriveRemoval()
+		if err != nil {
+			continue
+		}
+		for _, driveID := range removal.DriveIDs {
+			if driveID == id {
+				return h.AsyncResultID, nil
+			}
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testDriveId int64 = 31
+const testReplacementDriveId int64 = 45
+
+func testDrive(id int64, slot int64, status string) map[string]interface{} {
+	return map[string]interface{}{
+		"driveID":  id,
+		"nodeID":   testNodeId,
+		"slot":     slot,
+		"status":   status,
+		"type":     "block",
+		"capacity": int64(300069052416),
+		"serial":   "scsi-SATA_SAMSUNG_MZ7LM48S1UJNX0H",
+	}
+}
+
+func buildDrivesResponse(drives ...map[string]interface{}) SFResponse {
+	return buildSFResponseWrapper(map[string]interface{}{"Drives": drives})
+}
+
+func TestListDrives(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMock(t, c, buildDrivesResponse(testDrive(testDriveId, 3, DriveStatusActive)))
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ListDrives(ctx)
+	require.Nil(t, err)
+	require.Len(t, resp, 1)
+	require.Equal(t, testDriveId, resp[0].DriveID)
+	require.Equal(t, int64(3), resp[0].Slot)
+	require.Equal(t, DriveStatusActive, resp[0].Status)
+}
+
+func TestGetDriveByIdError(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMock(t, c, buildDrivesResponse(testDrive(testDriveId, 3, DriveStatusActive)))
+	defer mockReset()
+
+	ctx := context.Background()
+	_, err := c.GetDriveById(ctx, 99)
+	require.NotNil(t, err)
+	require.Equal(t, ErrDriveIDDoesNotExist, err.(*RequestError).Name)
+}
+
+func TestAddDrives(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"AsyncHandle": 12})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := AddDrivesRequest{Drives: []NewDrive{{DriveID: testDriveId}}}
+	resp, err := c.AddDrives(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, AsyncResultID(12), resp)
+	drives := (*requests)[0].Params["drives"].([]interface{})
+	require.Equal(t, float64(testDriveId), drives[0].(map[string]interface{})["driveID"])
+}
+
+func TestRemoveDrives(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"AsyncHandle": 13})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.RemoveDrives(ctx, RemoveDrivesRequest{Drives: []int64{testDriveId}})
+	require.Nil(t, err)
+	require.Equal(t, AsyncResultID(13), resp)
+	require.Equal(t, []interface{}{float64(testDriveId)}, (*requests)[0].Params["drives"])
+}
+
+func TestGetDriveStats(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"DriveStats": map[string]interface{}{
+		"driveID":              testDriveId,
+		"readOps":              100,
+		"lifeRemainingPercent": 97,
+	}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetDriveStats(ctx, testDriveId)
+	require.Nil(t, err)
+	require.Equal(t, testDriveId, resp.DriveID)
+	require.Equal(t, int64(100), resp.ReadOps)
+}
+
+func TestListDriveStats(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"DriveStats": []map[string]interface{}{
+		{"driveID": testDriveId, "readOps": 100},
+		{"driveID": testReplacementDriveId, "readOps": 5},
+	}})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ListDriveStats(ctx, []int64{testDriveId, testReplacementDriveId})
+	require.Nil(t, err)
+	require.Len(t, resp, 2)
+	require.Equal(t, int64(5), resp[1].ReadOps)
+	require.Len(t, (*requests)[0].Params["drives"], 2)
+}
+
+func TestSecureEraseDrives(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"AsyncHandle": 14})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.SecureEraseDrives(ctx, []int64{testDriveId})
+	require.Nil(t, err)
+	require.Equal(t, AsyncResultID(14), resp)
+	require.Equal(t, "SecureEraseDrives", (*requests)[0].Method)
+}
+
+func TestResetDrives(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"Details": map[string]interface{}{
+		"drives": []map[string]interface{}{{"drive": "/dev/slot0", "returnCode": 0}},
+	}})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	_, err := c.ResetDrives(ctx, []string{"/dev/slot0", "/dev/slot1"}, true)
+	require.Nil(t, err)
+	require.Equal(t, "/dev/slot0,/dev/slot1", (*requests)[0].Params["drives"])
+	require.Equal(t, true, (*requests)[0].Params["force"])
+}
+
+func TestReplaceDrive(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildDrivesResponse(testDrive(testDriveId, 3, DriveStatusFailed)),
+		buildSFResponseWrapper(map[string]interface{}{"AsyncHandle": 13}),
+		buildAsyncStatus(AsyncResultStatusComplete, map[string]interface{}{"resultType": DriveRemoval}),
+		buildDrivesResponse(
+			testDrive(testReplacementDriveId-1, 4, DriveStatusAvailable),
+			testDrive(testReplacementDriveId, 3, DriveStatusAvailable),
+		),
+		buildSFResponseWrapper(map[string]interface{}{"AsyncHandle": 14}),
+	)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ReplaceDrive(ctx, DriveReplacementRequest{FailedDriveID: testDriveId, WaitOptions: testAsyncWaitOptions})
+	require.Nil(t, err)
+	require.Equal(t, testDriveId, resp.RemovedDriveID)
+	require.Equal(t, testReplacementDriveId, resp.AddedDriveID)
+	require.Equal(t, AsyncResultID(13), resp.RemovalHandle)
+	require.Equal(t, AsyncResultID(14), resp.AddHandle)
+	methods := []string{}
+	for _, r := range *requests {
+		methods = append(methods, r.Method)
+	}
+	require.Equal(t, []string{"ListDrives", "RemoveDrives", "GetAsyncResult", "ListDrives", "AddDrives"}, methods)
+}
+
+func TestReplaceDriveAlreadyRemoving(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildDrivesResponse(testDrive(testDriveId, 3, DriveStatusRemoving)),
+		buildSFResponseWrapper(map[string]interface{}{"AsyncHandles": []map[string]interface{}{
+			{"asyncResultID": 12, "completed": true, "resultType": DriveRemoval, "data": map[string]interface{}{"driveIDs": []int64{testDriveId}}},
+			{"asyncResultID": 13, "completed": false, "resultType": DriveRemoval, "data": map[string]interface{}{"driveIDs": []int64{testDriveId}}},
+		}}),
+		buildAsyncStatus(AsyncResultStatusComplete, map[string]interface{}{"resultType": DriveRemoval}),
+		buildDrivesResponse(testDrive(testReplacementDriveId, 3, DriveStatusAvailable)),
+		buildSFResponseWrapper(map[string]interface{}{"AsyncHandle": 14}),
+	)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ReplaceDrive(ctx, DriveReplacementRequest{FailedDriveID: testDriveId, WaitOptions: testAsyncWaitOptions})
+	require.Nil(t, err)
+	require.Equal(t, AsyncResultID(13), resp.RemovalHandle)
+	require.Equal(t, "ListAsyncResults", (*requests)[1].Method)
+	require.Equal(t, float64(13), (*requests)[2].Params["asyncHandle"])
+}
+
+func TestReplaceDriveErrors(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMockSequence(c,
+		buildDrivesResponse(testDrive(testDriveId, 3, DriveStatusAvailable)),
+		buildDrivesResponse(testDrive(testDriveId, 3, DriveStatusAvailable), testDrive(testReplacementDriveId, 3, DriveStatusActive)),
+	)
+	defer mockReset()
+
+	ctx := context.Background()
+	_, err := c.ReplaceDrive(ctx, DriveReplacementRequest{FailedDriveID: testDriveId, ReplacementDriveID: testReplacementDriveId})
+	require.NotNil(t, err)
+	require.Equal(t, ErrDriveNotAvailable, err.(*RequestError).Name)
+
+	_, err = c.ReplaceDrive(ctx, DriveReplacementRequest{FailedDriveID: testDriveId})
+	require.NotNil(t, err)
+	require.Equal(t, ErrNoReplacementDrive, err.(*RequestError).Name)
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// default event stream options
+const (
+	defaultEventPollInterval     = time.Second * 10
+	defaultEventMaxRetryInterval = time.Minute * 5
+	defaultEventPageSize         = 1000
+)
+
+// ReportTime parses TimeOfReport, the time the event was reported to the cluster
+func (e EventInfo) ReportTime() (time.Time, error) {
+	return parseEventTime(e.TimeOfReport)
+}
+
+// PublishTime parses TimeOfPublish, the time the event was added to the cluster event log
+func (e EventInfo) PublishTime() (time.Time, error) {
+	return parseEventTime(e.TimeOfPublish)
+}
+
+func parseEventTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "parsing event time")
+	}
+	return t, nil
+}
+
+type EventStreamOptions struct {
+	// Checkpoint to resume from, only events with a greater ID are returned. When 0, or when the
+	// events following the checkpoint were pruned from the log, the stream starts at the oldest event
+	// in the cluster event log.
+	AfterEventID int64
+	// Delay between polls once the stream has caught up with the event log
+	PollInterval time.Duration
+	// Upper bound for the delay between polls after consecutive failures
+	MaxRetryInterval time.Duration
+	// Number of event IDs requested per poll, 1000 by default. Windows of the event log are
+	// requested back to back until the stream has caught up.
+	PageSize int64
+	// Optional filters, applied by the stream so that every window of the event log is read whole
+	EventType string
+	NodeID    int64
+	DriveID   int64
+	// Called when a poll fails with an error the stream retries
+	OnError func(error)
+}
+
+func (o *EventStreamOptions) setDefaults() {
+	if o.PollInterval <= 0 {
+		o.PollInterval = defaultEventPollInterval
+	}
+	if o.MaxRetryInterval <= 0 {
+		o.MaxRetryInterval = defaultEventMaxRetryInterval
+	}
+	if o.MaxRetryInterval < o.PollInterval {
+		o.MaxRetryInterval = o.PollInterval
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = defaultEventPageSize
+	}
+}
+
+// EventStream tails the cluster event log in EventID order. It isn't safe for concurrent use.
+type EventStream struct {
+	client     *Client
+	opts       EventStreamOptions
+	checkpoint int64
+	// ID of the last event read from the event log, including events filtered out
+	scanned  int64
+	pending  []EventInfo
+	failures int
+}
+
+func (c *Client) NewEventStream(opts EventStreamOptions) *EventStream {
+	opts.setDefaults()
+	return &EventStream{
+		client:     c,
+		opts:       opts,
+		checkpoint: opts.AfterEventID,
+		scanned:    opts.AfterEventID,
+	}
+}
+
+// Checkpoint returns the ID of the last event returned by Next, or of the last event filtered out
+// since. Persist it once the event has been handled and pass it as AfterEventID to resume without
+// missing events.
+func (s *EventStream) Checkpoint() int64 {
+	return s.checkpoint
+}
+
+// Next returns the next event, waiting for one to be published if needed. Failed polls are retried
+// with backoff unless the error is caused by the request itself, in which case it is returned.
+// Next only returns early when ctx is done.
+func (s *EventStream) Next(ctx context.Context) (result EventInfo, err error) {
+	for len(s.pending) == 0 {
+		delay := s.opts.PollInterval
+		events, full, err := s.poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil || !isTransientError(err) {
+				return result, err
+			}
+			if s.opts.OnError != nil {
+				s.opts.OnError(err)
+			}
+			s.failures++
+			delay = s.retryDelay()
+		} else {
+			s.failures = 0
+			s.pending = events
+			if len(events) > 0 {
+				break
+			}
+			// Every event up to the end of the window was filtered out
+			s.checkpoint = s.scanned
+			if full {
+				continue
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, errors.Wrap(ctx.Err(), "waiting for cluster events")
+		case <-timer.C:
+		}
+	}
+
+	result = s.pending[0]
+	s.pending = s.pending[1:]
+	s.checkpoint = result.EventID
+	return result, nil
+}
+
+// poll reads the window of the event log following the last event read and returns the matching
+// events. The window holds at most PageSize event IDs so the cluster never truncates it, full
+// reports whether it was full and the next window may hold more events already.
+func (s *EventStream) poll(ctx context.Context) (events []EventInfo, full bool, err error) {
+	req := ListEventsRequest{
+		StartEventID: s.scanned + 1,
+		EndEventID:   s.scanned + s.opts.PageSize,
+		MaxEvents:    s.opts.PageSize,
+	}
+	ler, err := s.client.GetEventList(ctx, req)
+	if err != nil {
+		return nil, false, err
+	}
+	events = make([]EventInfo, 0, len(ler.Events))
+	last := s.scanned
+	for _, e := range ler.Events {
+		if e.EventID <= s.scanned || e.EventID > req.EndEventID {
+			continue
+		}
+		if e.EventID > last {
+			last = e.EventID
+		}
+		if s.matches(e) {
+			events = append(events, e)
+		}
+	}
+	full = int64(len(ler.Events)) >= s.opts.PageSize || last == req.EndEventID
+	if last == s.scanned {
+		// The window is empty, either the stream caught up or the events were pruned from the log
+		// or skipped an ID range. Move to the next existing event if there is one.
+		next, err := s.nextEventID(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		if next > req.EndEventID {
+			last, full = next-1, true
+		}
+	}
+	s.scanned = last
+	// The cluster returns the most recent events first
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].EventID < events[j].EventID
+	})
+	return events, full, nil
+}
+
+// nextEventID returns the lowest ID of the events after the last event read, or 0 if there are none
+func (s *EventStream) nextEventID(ctx context.Context) (int64, error) {
+	ler, err := s.client.GetEventList(ctx, ListEventsRequest{StartEventID: s.scanned + 1, MaxEvents: 1})
+	if err != nil || len(ler.Events) == 0 {
+		return 0, err
+	}
+	// The event returned may not be the lowest one, narrow the range down until it only holds the
+	// lowest event
+	low, high := s.scanned+1, ler.Events[0].EventID
+	for low < high {
+		mid := low + (high-low)/2
+		found, err := s.hasEvents(ctx, low, mid)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low, nil
+}
+
+func (s *EventStream) hasEvents(ctx context.Context, start int64, end int64) (bool, error) {
+	ler, err := s.client.GetEventList(ctx, ListEventsRequest{StartEventID: start, EndEventID: end, MaxEvents: 1})
+	if err != nil {
+		return false, err
+	}
+	return len(ler.Events) > 0, nil
+}
+
+func (s *EventStream) matches(e EventInfo) bool {
+	if s.opts.EventType != "" && e.EventInfoType != s.opts.EventType {
+		return false
+	}
+	if s.opts.NodeID != 0 && e.NodeID != s.opts.NodeID {
+		return false
+	}
+	if s.opts.DriveID != 0 && e.DriveID != s.opts.DriveID && !containsInt64(e.DriveIDs, s.opts.DriveID) {
+		return false
+	}
+	return true
+}
+
+func containsInt64(values []int64, v int64) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *EventStream) retryDelay() time.Duration {
+	delay := s.opts.PollInterval
+	for i := 1; i < s.failures && delay < s.opts.MaxRetryInterval; i++ {
+		delay *= 2
+	}
+	if delay > s.opts.MaxRetryInterval {
+		delay = s.opts.MaxRetryInterval
+	}
+	return delay
+}
+
+// isTransientError reports whether a request failing with err may succeed when sent again
+func isTransientError(err error) bool {
+	var reqErr *RequestError
+	var notFound *ResourceNotFoundError
+	var certErr *CertificateVerificationError
+	return !errors.As(err, &reqErr) && !errors.As(err, &notFound) && !errors.As(err, &certErr)
+}
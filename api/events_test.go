@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent(id int64, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"eventID":       id,
+		"eventInfoType": "apiEvent",
+		"message":       message,
+		"timeOfReport":  "2021-05-24T15:18:14.418374Z",
+		"timeOfPublish": "2021-05-24T15:18:15Z",
+	}
+}
+
+func buildEventsResponse(events ...map[string]interface{}) SFResponse {
+	return buildSFResponseWrapper(map[string]interface{}{"EventQueueType": "event", "Events": events})
+}
+
+// activateEventLogMock answers ListEvents like a cluster whose event log holds the given IDs, most
+// recent events first
+func activateEventLogMock(c *Client, ids ...int64) (requests *int, mockReset func()) {
+	httpmock.ActivateNonDefault(c.HTTPClient.GetClient())
+	sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+	count := 0
+	httpmock.RegisterResponder("POST", c.ApiUrl, func(req *http.Request) (*http.Response, error) {
+		body := struct {
+			Id     int64             `json:"id"`
+			Params ListEventsRequest `json:"params"`
+		}{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		count++
+		p := body.Params
+		events := []map[string]interface{}{}
+		for _, id := range ids {
+			if id < p.StartEventID || (p.EndEventID != 0 && id > p.EndEventID) {
+				continue
+			}
+			if p.MaxEvents != 0 && int64(len(events)) == p.MaxEvents {
+				break
+			}
+			events = append(events, testEvent(id, fmt.Sprintf("event %d", id)))
+		}
+		resp := buildEventsResponse(events...)
+		resp.Id = body.Id
+		return httpmock.NewJsonResponse(http.StatusOK, resp)
+	})
+	return &count, httpmock.DeactivateAndReset
+}
+
+func TestEventInfoTimes(t *testing.T) {
+	e := EventInfo{
+		TimeOfReport:  "2021-05-24T15:18:14.418374Z",
+		TimeOfPublish: "2021-05-24T15:18:15Z",
+	}
+	report, err := e.ReportTime()
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2021, 5, 24, 15, 18, 14, 418374000, time.UTC), report)
+	publish, err := e.PublishTime()
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2021, 5, 24, 15, 18, 15, 0, time.UTC), publish)
+
+	e.TimeOfReport = ""
+	_, err = e.ReportTime()
+	require.NotNil(t, err)
+}
+
+func TestEventStream(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildEventsResponse(testEvent(12, "third"), testEvent(11, "second"), testEvent(10, "first")),
+		buildEventsResponse(),
+		buildEventsResponse(),
+		buildEventsResponse(testEvent(13, "fourth")),
+	)
+	defer mockReset()
+
+	s := c.NewEventStream(EventStreamOptions{AfterEventID: 9, PollInterval: time.Millisecond})
+	ctx := context.Background()
+	messages := []string{}
+	for i := 0; i < 4; i++ {
+		e, err := s.Next(ctx)
+		require.Nil(t, err)
+		messages = append(messages, e.Message)
+		require.Equal(t, e.EventID, s.Checkpoint())
+	}
+	require.Equal(t, []string{"first", "second", "third", "fourth"}, messages)
+	// The empty window is followed by a lookup of the next event, there is none yet
+	require.Len(t, *requests, 4)
+	require.Equal(t, float64(10), (*requests)[0].Params["startEventID"])
+	require.Equal(t, float64(13), (*requests)[1].Params["startEventID"])
+	require.Equal(t, float64(13), (*requests)[2].Params["startEventID"])
+	require.NotContains(t, (*requests)[2].Params, "endEventID")
+	require.Equal(t, float64(1), (*requests)[2].Params["maxEvents"])
+	require.Equal(t, float64(13), (*requests)[3].Params["startEventID"])
+	require.Equal(t, float64(9+defaultEventPageSize), (*requests)[0].Params["endEventID"])
+	require.Equal(t, float64(defaultEventPageSize), (*requests)[0].Params["maxEvents"])
+}
+
+func TestEventStreamFullPage(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildEventsResponse(testEvent(3, "third"), testEvent(2, "second"), testEvent(1, "first")),
+		buildEventsResponse(testEvent(5, "fifth"), testEvent(4, "fourth")),
+	)
+	defer mockReset()
+
+	s := c.NewEventStream(EventStreamOptions{PageSize: 3, PollInterval: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	messages := []string{}
+	for i := 0; i < 5; i++ {
+		e, err := s.Next(ctx)
+		require.Nil(t, err)
+		messages = append(messages, e.Message)
+	}
+	// The second window is requested without waiting for the poll interval
+	require.Equal(t, []string{"first", "second", "third", "fourth", "fifth"}, messages)
+	require.Len(t, *requests, 2)
+	require.Equal(t, float64(1), (*requests)[0].Params["startEventID"])
+	require.Equal(t, float64(3), (*requests)[0].Params["endEventID"])
+	require.Equal(t, float64(3), (*requests)[0].Params["maxEvents"])
+	require.Equal(t, float64(4), (*requests)[1].Params["startEventID"])
+	require.Equal(t, float64(6), (*requests)[1].Params["endEventID"])
+}
+
+func TestEventStreamPrunedLog(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateEventLogMock(c, 5000, 5001, 5002, 9000)
+	defer mockReset()
+
+	s := c.NewEventStream(EventStreamOptions{PageSize: 10, PollInterval: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	ids := []int64{}
+	for i := 0; i < 4; i++ {
+		e, err := s.Next(ctx)
+		require.Nil(t, err)
+		ids = append(ids, e.EventID)
+	}
+	// The stream skips the pruned range and the gap after 5002
+	require.Equal(t, []int64{5000, 5001, 5002, 9000}, ids)
+	require.Equal(t, int64(9000), s.Checkpoint())
+	require.Less(t, *requests, 60)
+}
+
+func TestEventStreamFilters(t *testing.T) {
+	c := getTestClient(t)
+	driveEvent := testEvent(3, "drive")
+	driveEvent["eventInfoType"] = "driveEvent"
+	driveEvent["driveIDs"] = []int64{7, 8}
+	requests, mockReset := activateRecordingMock(c,
+		buildEventsResponse(testEvent(2, "second"), testEvent(1, "first")),
+		buildEventsResponse(driveEvent),
+	)
+	defer mockReset()
+
+	s := c.NewEventStream(EventStreamOptions{PageSize: 2, EventType: "driveEvent", DriveID: 8, PollInterval: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	e, err := s.Next(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "drive", e.Message)
+	require.Equal(t, int64(3), s.Checkpoint())
+	// A full window without matching events doesn't stall the stream
+	require.Len(t, *requests, 2)
+	require.NotContains(t, (*requests)[0].Params, "eventType")
+	require.Equal(t, float64(3), (*requests)[1].Params["startEventID"])
+}
+
+func TestEventStreamRetriesTransientErrors(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMockSequence(c,
+		SFResponse{Error: SFAPIError{Code: 500, Name: ErrUnexpectedServerError, Message: "busy"}},
+		buildEventsResponse(testEvent(10, "first")),
+	)
+	defer mockReset()
+
+	failures := 0
+	s := c.NewEventStream(EventStreamOptions{
+		PollInterval: time.Millisecond,
+		OnError:      func(err error) { failures++ },
+	})
+	e, err := s.Next(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, int64(10), e.EventID)
+	require.Equal(t, 1, failures)
+}
+
+func TestEventStreamRequestError(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMockHttpErr(c, http.StatusUnauthorized)
+	defer mockReset()
+
+	s := c.NewEventStream(EventStreamOptions{PollInterval: time.Millisecond})
+	_, err := s.Next(context.Background())
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrInvalidCredentials, reqErr.Name)
+}
+
+func TestEventStreamContextDone(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMock(t, c, buildEventsResponse())
+	defer mockReset()
+
+	s := c.NewEventStream(EventStreamOptions{AfterEventID: 20, PollInterval: time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	_, err := s.Next(ctx)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Equal(t, int64(20), s.Checkpoint())
+}
+
+func TestEventStreamRetryDelay(t *testing.T) {
+	s := (&Client{}).NewEventStream(EventStreamOptions{PollInterval: time.Second, MaxRetryInterval: time.Second * 5})
+	delays := []time.Duration{}
+	for s.failures = 1; s.failures <= 5; s.failures++ {
+		delays = append(delays, s.retryDelay())
+	}
+	require.Equal(t, []time.Duration{time.Second, time.Second * 2, time.Second * 4, time.Second * 5, time.Second * 5}, delays)
+}
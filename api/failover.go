@@ -0,0 +1,339 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ReplicationStep is one action of a ReplicationPlan
+type ReplicationStep struct {
+	Description string
+	// Optional check of the replication state run right before the step
+	check func(ctx context.Context) error
+	run   func(ctx context.Context) error
+}
+
+// ReplicationPlan is the list of steps of a failover or failback, built once the pre-checks passed
+type ReplicationPlan struct {
+	Name  string
+	Steps []ReplicationStep
+}
+
+func (p *ReplicationPlan) add(description string, run func(ctx context.Context) error) {
+	p.addChecked(description, nil, run)
+}
+
+func (p *ReplicationPlan) addChecked(description string, check func(ctx context.Context) error, run func(ctx context.Context) error) {
+	p.Steps = append(p.Steps, ReplicationStep{Description: description, check: check, run: run})
+}
+
+// Print writes the numbered steps of the plan to w
+func (p *ReplicationPlan) Print(w io.Writer) {
+	fmt.Fprintf(w, "%s:\n", p.Name)
+	for i, s := range p.Steps {
+		fmt.Fprintf(w, "  %d. %s\n", i+1, s.Description)
+	}
+}
+
+// Execute runs the steps in order and stops at the first failure, including a failed check of the
+// replication state before a step. Completed steps aren't undone, the error names the failed step
+// so the remaining ones can be finished by hand. Progress is written to w unless it is nil.
+func (p *ReplicationPlan) Execute(ctx context.Context, w io.Writer) (err error) {
+	for i, s := range p.Steps {
+		if w != nil {
+			fmt.Fprintf(w, "%s: step %d/%d: %s\n", p.Name, i+1, len(p.Steps), s.Description)
+		}
+		if s.check != nil {
+			if err = s.check(ctx); err != nil {
+				return errors.Wrapf(err, "%s step %d (%s) check", p.Name, i+1, s.Description)
+			}
+		}
+		if err = s.run(ctx); err != nil {
+			return errors.Wrapf(err, "%s step %d (%s)", p.Name, i+1, s.Description)
+		}
+	}
+	return nil
+}
+
+type FailoverOptions struct {
+	// Volume on the secondary cluster. Defaults to the remote volume of the primary volume's pair.
+	SecondaryVolumeID int64
+	// Make the primary volume the replication target so data flows from the secondary cluster
+	Reverse bool
+	// The primary cluster is unreachable, only the secondary cluster is modified.
+	// SecondaryVolumeID is required and Reverse isn't supported.
+	Unplanned bool
+	// Skip the replication state pre-checks, before planning and before each step
+	Force bool
+	// Print the plan instead of executing it
+	DryRun bool
+	// Destination of the dry run plan, os.Stdout by default, and of the progress of an executed
+	// plan, which isn't written when nil
+	Output io.Writer
+}
+
+type FailbackOptions struct {
+	// Volume on the secondary cluster. Defaults to the remote volume of the primary volume's pair.
+	SecondaryVolumeID int64
+	// Skip the replication state pre-checks, before planning and before each step
+	Force bool
+	// Print the plan instead of executing it
+	DryRun bool
+	// Destination of the dry run plan, os.Stdout by default, and of the progress of an executed
+	// plan, which isn't written when nil
+	Output io.Writer
+	// Delay between checks of the replication state while resyncing to the primary cluster
+	PollInterval time.Duration
+	// Optional limit on the resync, in addition to any deadline on the context
+	Timeout time.Duration
+}
+
+func preCheckFailed(format string, a ...interface{}) error {
+	return BuildRequestError(ErrReplicationPreCheckFailed, fmt.Sprintf(format, a...))
+}
+
+// pairedVolume returns the volume and its pair, failing if the volume isn't paired
+func pairedVolume(ctx context.Context, c *Client, volID int64) (*Volume, *VolumePair, error) {
+	volume, err := c.GetVolumeById(ctx, volID)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "getting volume %d on %s", volID, c.Target)
+	}
+	if len(volume.VolumePairs) == 0 {
+		return nil, nil, BuildRequestError(ErrVolumeNotPaired, fmt.Sprintf("Volume %d on %s is not paired", volID, c.Target))
+	}
+	return volume, &volume.VolumePairs[0], nil
+}
+
+func setVolumeAccess(c *Client, volID int64, access string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := c.ModifyVolume(ctx, ModifyVolumeRequest{VolumeID: volID, Access: access})
+		return err
+	}
+}
+
+func pauseVolumePair(c *Client, volID int64) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return c.PauseVolumePair(ctx, volID)
+	}
+}
+
+func resumeVolumePair(c *Client, volID int64) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return c.ResumeVolumePair(ctx, volID)
+	}
+}
+
+// requirePairState returns a check failing unless the pair of the volume is in one of the states
+func requirePairState(c *Client, volID int64, states ...string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, pair, err := pairedVolume(ctx, c, volID)
+		if err != nil {
+			return err
+		}
+		state := pair.RemoteReplication.State
+		for _, s := range states {
+			if state == s {
+				return nil
+			}
+		}
+		return preCheckFailed("Replication of volume %d on %s is %s, expected %s",
+			volID, c.Target, state, strings.Join(states, " or "))
+	}
+}
+
+// requireVolumeAccess returns a check failing unless the volume has the given access
+func requireVolumeAccess(c *Client, volID int64, access string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		volume, err := c.GetVolumeById(ctx, volID)
+		if err != nil {
+			return errors.Wrapf(err, "getting volume %d on %s", volID, c.Target)
+		}
+		if volume.Access != access {
+			return preCheckFailed("Volume %d on %s has %s access, expected %s", volID, c.Target, volume.Access, access)
+		}
+		return nil
+	}
+}
+
+func runPlan(ctx context.Context, plan *ReplicationPlan, dryRun bool, w io.Writer) error {
+	if dryRun {
+		if w == nil {
+			w = os.Stdout
+		}
+		plan.Print(w)
+		return nil
+	}
+	return plan.Execute(ctx, w)
+}
+
+// PlanFailover checks the replication of a primary volume and returns the steps moving its
+// workload to the secondary cluster: pause the pair, demote the primary volume to readOnly, or to
+// the replication target with Reverse, promote the secondary volume to readWrite and, with Reverse,
+// resume the pair. An unplanned failover only promotes the secondary volume.
+func PlanFailover(ctx context.Context, primary *Client, secondary *Client, primaryVolID int64, opts FailoverOptions) (result *ReplicationPlan, err error) {
+	if opts.Unplanned {
+		if opts.Reverse {
+			return nil, preCheckFailed("An unplanned failover can't reverse the replication")
+		}
+		if opts.SecondaryVolumeID == 0 {
+			return nil, preCheckFailed("An unplanned failover requires the secondary volume ID")
+		}
+	} else {
+		_, pair, err := pairedVolume(ctx, primary, primaryVolID)
+		if err != nil {
+			return nil, err
+		}
+		if opts.SecondaryVolumeID == 0 {
+			opts.SecondaryVolumeID = pair.RemoteVolumeID
+		} else if opts.SecondaryVolumeID != pair.RemoteVolumeID {
+			return nil, preCheckFailed("Volume %d on %s is paired with volume %d, not %d",
+				primaryVolID, primary.Target, pair.RemoteVolumeID, opts.SecondaryVolumeID)
+		}
+		state := pair.RemoteReplication.State
+		if !opts.Force && state != ReplicationStateActive && state != ReplicationStatePausedManual {
+			return nil, preCheckFailed("Replication of volume %d on %s is %s, the secondary volume may be behind",
+				primaryVolID, primary.Target, state)
+		}
+	}
+
+	secondaryVolume, secondaryPair, err := pairedVolume(ctx, secondary, opts.SecondaryVolumeID)
+	if err != nil {
+		return nil, err
+	}
+	if secondaryPair.RemoteVolumeID != primaryVolID {
+		return nil, preCheckFailed("Volume %d on %s is paired with volume %d, not %d",
+			opts.SecondaryVolumeID, secondary.Target, secondaryPair.RemoteVolumeID, primaryVolID)
+	}
+	if secondaryVolume.Access != VolumeAccessPolicyReplicationTarget {
+		return nil, preCheckFailed("Volume %d on %s has %s access, it should be the replication target",
+			opts.SecondaryVolumeID, secondary.Target, secondaryVolume.Access)
+	}
+
+	secondaryID := opts.SecondaryVolumeID
+	result = &ReplicationPlan{Name: fmt.Sprintf("Failover of volume %d to %s", primaryVolID, secondary.Target)}
+	if opts.Unplanned {
+		result.add(fmt.Sprintf("Set the access of volume %d on %s to %s", secondaryID, secondary.Target, VolumeAccessPolicyReadWrite),
+			setVolumeAccess(secondary, secondaryID, VolumeAccessPolicyReadWrite))
+		return result, nil
+	}
+
+	// The primary volume stops accepting writes before the secondary volume does, so both are
+	// never readWrite at once
+	demotedAccess := VolumeAccessPolicyReadOnly
+	if opts.Reverse {
+		demotedAccess = VolumeAccessPolicyReplicationTarget
+	}
+	var checkPaused, checkActive func(ctx context.Context) error
+	if !opts.Force {
+		checkActive = requirePairState(primary, primaryVolID, ReplicationStateActive, ReplicationStatePausedManual)
+		checkPaused = requirePairState(primary, primaryVolID, ReplicationStatePausedManual)
+	}
+	result.addChecked(fmt.Sprintf("Pause the pair of volume %d on %s", primaryVolID, primary.Target),
+		checkActive, pauseVolumePair(primary, primaryVolID))
+	result.addChecked(fmt.Sprintf("Set the access of volume %d on %s to %s", primaryVolID, primary.Target, demotedAccess),
+		checkPaused, setVolumeAccess(primary, primaryVolID, demotedAccess))
+	result.addChecked(fmt.Sprintf("Set the access of volume %d on %s to %s", secondaryID, secondary.Target, VolumeAccessPolicyReadWrite),
+		requireVolumeAccess(primary, primaryVolID, demotedAccess),
+		setVolumeAccess(secondary, secondaryID, VolumeAccessPolicyReadWrite))
+	if opts.Reverse {
+		result.addChecked(fmt.Sprintf("Resume the pair of volume %d on %s", primaryVolID, primary.Target),
+			requireVolumeAccess(secondary, secondaryID, VolumeAccessPolicyReadWrite),
+			resumeVolumePair(primary, primaryVolID))
+	}
+	return result, nil
+}
+
+// Failover runs the plan returned by PlanFailover, or only prints it with DryRun
+func Failover(ctx context.Context, primary *Client, secondary *Client, primaryVolID int64, opts FailoverOptions) (result *ReplicationPlan, err error) {
+	result, err = PlanFailover(ctx, primary, secondary, primaryVolID, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result, runPlan(ctx, result, opts.DryRun, opts.Output)
+}
+
+// PlanFailback checks the replication of a failed over volume and returns the steps moving its
+// workload back to the primary cluster: resync the primary volume from the secondary, pause the
+// pair, swap the volume accesses and resume the pair in the original direction.
+func PlanFailback(ctx context.Context, primary *Client, secondary *Client, primaryVolID int64, opts FailbackOptions) (result *ReplicationPlan, err error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultReplicationPollInterval
+	}
+	primaryVolume, pair, err := pairedVolume(ctx, primary, primaryVolID)
+	if err != nil {
+		return nil, err
+	}
+	if opts.SecondaryVolumeID == 0 {
+		opts.SecondaryVolumeID = pair.RemoteVolumeID
+	} else if opts.SecondaryVolumeID != pair.RemoteVolumeID {
+		return nil, preCheckFailed("Volume %d on %s is paired with volume %d, not %d",
+			primaryVolID, primary.Target, pair.RemoteVolumeID, opts.SecondaryVolumeID)
+	}
+	secondaryID := opts.SecondaryVolumeID
+	secondaryVolume, _, err := pairedVolume(ctx, secondary, secondaryID)
+	if err != nil {
+		return nil, err
+	}
+	if secondaryVolume.Access != VolumeAccessPolicyReadWrite {
+		return nil, preCheckFailed("Volume %d on %s has %s access, it should have been failed over",
+			secondaryID, secondary.Target, secondaryVolume.Access)
+	}
+	state := pair.RemoteReplication.State
+	switch state {
+	case ReplicationStateActive, ReplicationStatePausedManual, ReplicationStatePausedManualRemote:
+	default:
+		if !opts.Force {
+			return nil, preCheckFailed("Replication of volume %d on %s is %s, it can't be resynced",
+				primaryVolID, primary.Target, state)
+		}
+	}
+
+	result = &ReplicationPlan{Name: fmt.Sprintf("Failback of volume %d to %s", primaryVolID, primary.Target)}
+	if primaryVolume.Access != VolumeAccessPolicyReplicationTarget {
+		result.add(fmt.Sprintf("Set the access of volume %d on %s to %s", primaryVolID, primary.Target, VolumeAccessPolicyReplicationTarget),
+			setVolumeAccess(primary, primaryVolID, VolumeAccessPolicyReplicationTarget))
+	}
+	switch state {
+	case ReplicationStatePausedManual:
+		result.add(fmt.Sprintf("Resume the pair of volume %d on %s", primaryVolID, primary.Target),
+			resumeVolumePair(primary, primaryVolID))
+	case ReplicationStatePausedManualRemote:
+		result.add(fmt.Sprintf("Resume the pair of volume %d on %s", secondaryID, secondary.Target),
+			resumeVolumePair(secondary, secondaryID))
+	}
+	result.add(fmt.Sprintf("Wait for volume %d on %s to be resynced from %s", primaryVolID, primary.Target, secondary.Target),
+		func(ctx context.Context) error {
+			_, err := waitForReplication(ctx, primary, primaryVolID, opts.PollInterval, opts.Timeout)
+			return err
+		})
+	result.add(fmt.Sprintf("Pause the pair of volume %d on %s", secondaryID, secondary.Target),
+		pauseVolumePair(secondary, secondaryID))
+	var checkPaused func(ctx context.Context) error
+	if !opts.Force {
+		checkPaused = requirePairState(secondary, secondaryID, ReplicationStatePausedManual)
+	}
+	result.addChecked(fmt.Sprintf("Set the access of volume %d on %s to %s", secondaryID, secondary.Target, VolumeAccessPolicyReplicationTarget),
+		checkPaused, setVolumeAccess(secondary, secondaryID, VolumeAccessPolicyReplicationTarget))
+	result.addChecked(fmt.Sprintf("Set the access of volume %d on %s to %s", primaryVolID, primary.Target, VolumeAccessPolicyReadWrite),
+		requireVolumeAccess(secondary, secondaryID, VolumeAccessPolicyReplicationTarget),
+		setVolumeAccess(primary, primaryVolID, VolumeAccessPolicyReadWrite))
+	result.addChecked(fmt.Sprintf("Resume the pair of volume %d on %s", secondaryID, secondary.Target),
+		requireVolumeAccess(primary, primaryVolID, VolumeAccessPolicyReadWrite),
+		resumeVolumePair(secondary, secondaryID))
+	return result, nil
+}
+
+// Failback runs the plan returned by PlanFailback, or only prints it with DryRun
+func Failback(ctx context.Context, primary *Client, secondary *Client, primaryVolID int64, opts FailbackOptions) (result *ReplicationPlan, err error) {
+	result, err = PlanFailback(ctx, primary, secondary, primaryVolID, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result, runPlan(ctx, result, opts.DryRun, opts.Output)
+}
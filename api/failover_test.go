@@ -0,0 +1,261 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func testPrimaryVolume(access string, state string) map[string]interface{} {
+	return testReplicationVolume(map[string]interface{}{
+		"access":      access,
+		"volumePairs": testReplicationPair(state),
+	})
+}
+
+func testSecondaryVolume(access string, state string) map[string]interface{} {
+	pairs := testReplicationPair(state)
+	pairs[0]["remoteVolumeID"] = testVolumeId
+	return testReplicationVolume(map[string]interface{}{
+		"volumeID":    testTargetVolumeId,
+		"access":      access,
+		"volumePairs": pairs,
+	})
+}
+
+func TestFailoverDryRun(t *testing.T) {
+	primary := getTestClient(t)
+	secondary := getTestRemoteClient(t)
+	primaryRequests, mockReset := activateRecordingMock(primary,
+		buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStateActive)))
+	defer mockReset()
+	secondaryRequests, _ := activateRecordingMock(secondary,
+		buildVolumesResponse(testSecondaryVolume(VolumeAccessPolicyReplicationTarget, ReplicationStateActive)))
+
+	out := bytes.Buffer{}
+	ctx := context.Background()
+	plan, err := Failover(ctx, primary, secondary, testVolumeId, FailoverOptions{Reverse: true, DryRun: true, Output: &out})
+	require.Nil(t, err)
+	require.Len(t, plan.Steps, 4)
+	require.Equal(t, `Failover of volume 3576 to remotehost:
+  1. Pause the pair of volume 3576 on localhost
+  2. Set the access of volume 3576 on localhost to replicationTarget
+  3. Set the access of volume 3776 on remotehost to readWrite
+  4. Resume the pair of volume 3576 on localhost
+`, out.String())
+	require.Equal(t, []string{"ListVolumes"}, requestMethods(*primaryRequests))
+	require.Equal(t, []string{"ListVolumes"}, requestMethods(*secondaryRequests))
+}
+
+func TestFailover(t *testing.T) {
+	primary := getTestClient(t)
+	secondary := getTestRemoteClient(t)
+	primaryRequests, mockReset := activateRecordingMock(primary,
+		buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStateActive)),
+		buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStateActive)),
+		buildSFResponseWrapper(map[string]interface{}{}),
+		buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStatePausedManual)),
+		buildSFResponseWrapper(map[string]interface{}{"Volume": testPrimaryVolume(VolumeAccessPolicyReadOnly, ReplicationStatePausedManual)}),
+		buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadOnly, ReplicationStatePausedManual)),
+	)
+	defer mockReset()
+	secondaryRequests, _ := activateRecordingMock(secondary,
+		buildVolumesResponse(testSecondaryVolume(VolumeAccessPolicyReplicationTarget, ReplicationStateActive)),
+		buildSFResponseWrapper(map[string]interface{}{"Volume": testSecondaryVolume(VolumeAccessPolicyReadWrite, ReplicationStatePausedManualRemote)}),
+	)
+
+	out := bytes.Buffer{}
+	ctx := context.Background()
+	_, err := Failover(ctx, primary, secondary, testVolumeId, FailoverOptions{Output: &out})
+	require.Nil(t, err)
+	require.Contains(t, out.String(), "step 3/3")
+	// The pair state is checked again before pausing and before demoting the primary volume, which
+	// must be readOnly before the secondary volume is promoted
+	require.Equal(t, []string{"ListVolumes", "ListVolumes", "ModifyVolumePair", "ListVolumes", "ModifyVolume", "ListVolumes"},
+		requestMethods(*primaryRequests))
+	require.Equal(t, true, (*primaryRequests)[2].Params["pausedManual"])
+	require.Equal(t, VolumeAccessPolicyReadOnly, (*primaryRequests)[4].Params["access"])
+	require.Equal(t, []string{"ListVolumes", "ModifyVolume"}, requestMethods(*secondaryRequests))
+	require.Equal(t, VolumeAccessPolicyReadWrite, (*secondaryRequests)[1].Params["access"])
+}
+
+func TestFailoverStepCheck(t *testing.T) {
+	primary := getTestClient(t)
+	secondary := getTestRemoteClient(t)
+	// The pair is still active after the pause, the primary volume must not be demoted
+	primaryRequests, mockReset := activateRecordingMock(primary,
+		buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStateActive)),
+		buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStateActive)),
+		buildSFResponseWrapper(map[string]interface{}{}),
+		buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStateActive)),
+	)
+	defer mockReset()
+	secondaryRequests, _ := activateRecordingMock(secondary,
+		buildVolumesResponse(testSecondaryVolume(VolumeAccessPolicyReplicationTarget, ReplicationStateActive)))
+
+	_, err := Failover(context.Background(), primary, secondary, testVolumeId, FailoverOptions{})
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrReplicationPreCheckFailed, reqErr.Name)
+	require.Contains(t, err.Error(), "step 2")
+	require.Contains(t, err.Error(), "expected PausedManual")
+	require.Len(t, *primaryRequests, 4)
+	require.Equal(t, []string{"ListVolumes"}, requestMethods(*secondaryRequests))
+}
+
+func TestFailoverUnplanned(t *testing.T) {
+	secondary := getTestRemoteClient(t)
+	requests, mockReset := activateRecordingMock(secondary,
+		buildVolumesResponse(testSecondaryVolume(VolumeAccessPolicyReplicationTarget, ReplicationStatePausedDisconnected)),
+		buildSFResponseWrapper(map[string]interface{}{"Volume": testSecondaryVolume(VolumeAccessPolicyReadWrite, ReplicationStatePausedDisconnected)}),
+	)
+	defer mockReset()
+
+	ctx := context.Background()
+	_, err := PlanFailover(ctx, nil, secondary, testVolumeId, FailoverOptions{Unplanned: true})
+	require.Equal(t, ErrReplicationPreCheckFailed, err.(*RequestError).Name)
+
+	opts := FailoverOptions{Unplanned: true, SecondaryVolumeID: testTargetVolumeId, Output: &bytes.Buffer{}}
+	plan, err := Failover(ctx, nil, secondary, testVolumeId, opts)
+	require.Nil(t, err)
+	require.Len(t, plan.Steps, 1)
+	require.Equal(t, []string{"ListVolumes", "ModifyVolume"}, requestMethods(*requests))
+}
+
+func TestFailoverPreChecks(t *testing.T) {
+	primary := getTestClient(t)
+	secondary := getTestRemoteClient(t)
+	mockReset := activateMock(t, primary, buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStatePausedDisconnected)))
+	defer mockReset()
+	activateMock(t, secondary, buildVolumesResponse(testSecondaryVolume(VolumeAccessPolicyReadWrite, ReplicationStatePausedDisconnected)))
+
+	ctx := context.Background()
+	_, err := PlanFailover(ctx, primary, secondary, testVolumeId, FailoverOptions{})
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrReplicationPreCheckFailed, reqErr.Name)
+	require.Contains(t, reqErr.Message, ReplicationStatePausedDisconnected)
+
+	_, err = PlanFailover(ctx, primary, secondary, testVolumeId, FailoverOptions{SecondaryVolumeID: 1})
+	require.True(t, errors.As(err, &reqErr))
+	require.Contains(t, reqErr.Message, "paired with volume 3776")
+
+	// Forcing skips the state check but the secondary volume must still be a replication target
+	_, err = PlanFailover(ctx, primary, secondary, testVolumeId, FailoverOptions{Force: true})
+	require.True(t, errors.As(err, &reqErr))
+	require.Contains(t, reqErr.Message, "readWrite access")
+}
+
+func TestFailoverSecondaryPairedElsewhere(t *testing.T) {
+	primary := getTestClient(t)
+	secondary := getTestRemoteClient(t)
+	mockReset := activateMock(t, primary, buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStateActive)))
+	defer mockReset()
+	volume := testSecondaryVolume(VolumeAccessPolicyReplicationTarget, ReplicationStateActive)
+	volume["volumePairs"].([]map[string]interface{})[0]["remoteVolumeID"] = 1
+	activateMock(t, secondary, buildVolumesResponse(volume))
+
+	ctx := context.Background()
+	_, err := PlanFailover(ctx, primary, secondary, testVolumeId, FailoverOptions{})
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrReplicationPreCheckFailed, reqErr.Name)
+	require.Contains(t, reqErr.Message, "Volume 3776 on remotehost is paired with volume 1, not 3576")
+
+	_, err = PlanFailover(ctx, nil, secondary, testVolumeId, FailoverOptions{Unplanned: true, SecondaryVolumeID: testTargetVolumeId})
+	require.True(t, errors.As(err, &reqErr))
+	require.Contains(t, reqErr.Message, "paired with volume 1")
+}
+
+func TestFailoverNotPaired(t *testing.T) {
+	primary := getTestClient(t)
+	secondary := getTestRemoteClient(t)
+	mockReset := activateMock(t, primary, buildVolumesResponse(testReplicationVolume(nil)))
+	defer mockReset()
+
+	_, err := PlanFailover(context.Background(), primary, secondary, testVolumeId, FailoverOptions{})
+	require.Equal(t, ErrVolumeNotPaired, err.(*RequestError).Name)
+}
+
+func TestFailback(t *testing.T) {
+	primary := getTestClient(t)
+	secondary := getTestRemoteClient(t)
+	primaryRequests, mockReset := activateRecordingMock(primary,
+		buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStatePausedManual)),
+		buildSFResponseWrapper(map[string]interface{}{"Volume": testPrimaryVolume(VolumeAccessPolicyReplicationTarget, ReplicationStatePausedManual)}),
+		buildSFResponseWrapper(map[string]interface{}{}),
+		buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReplicationTarget, ReplicationStateActive)),
+		buildSFResponseWrapper(map[string]interface{}{"Volume": testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStatePausedManualRemote)}),
+		buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReadWrite, ReplicationStatePausedManualRemote)),
+	)
+	defer mockReset()
+	secondaryRequests, _ := activateRecordingMock(secondary,
+		buildVolumesResponse(testSecondaryVolume(VolumeAccessPolicyReadWrite, ReplicationStatePausedManualRemote)),
+		buildSFResponseWrapper(map[string]interface{}{}),
+		buildVolumesResponse(testSecondaryVolume(VolumeAccessPolicyReadWrite, ReplicationStatePausedManual)),
+		buildSFResponseWrapper(map[string]interface{}{"Volume": testSecondaryVolume(VolumeAccessPolicyReplicationTarget, ReplicationStatePausedManual)}),
+		buildVolumesResponse(testSecondaryVolume(VolumeAccessPolicyReplicationTarget, ReplicationStatePausedManual)),
+		buildSFResponseWrapper(map[string]interface{}{}),
+	)
+
+	out := bytes.Buffer{}
+	ctx := context.Background()
+	plan, err := Failback(ctx, primary, secondary, testVolumeId, FailbackOptions{Output: &out, PollInterval: time.Millisecond})
+	require.Nil(t, err)
+	require.Len(t, plan.Steps, 7)
+	require.Equal(t, "Wait for volume 3576 on localhost to be resynced from remotehost", plan.Steps[2].Description)
+
+	require.Equal(t, []string{"ListVolumes", "ModifyVolume", "ModifyVolumePair", "ListVolumes", "ModifyVolume", "ListVolumes"}, requestMethods(*primaryRequests))
+	require.Equal(t, VolumeAccessPolicyReplicationTarget, (*primaryRequests)[1].Params["access"])
+	require.Equal(t, false, (*primaryRequests)[2].Params["pausedManual"])
+	require.Equal(t, VolumeAccessPolicyReadWrite, (*primaryRequests)[4].Params["access"])
+	require.Equal(t, []string{"ListVolumes", "ModifyVolumePair", "ListVolumes", "ModifyVolume", "ListVolumes", "ModifyVolumePair"},
+		requestMethods(*secondaryRequests))
+	require.Equal(t, true, (*secondaryRequests)[1].Params["pausedManual"])
+	require.Equal(t, VolumeAccessPolicyReplicationTarget, (*secondaryRequests)[3].Params["access"])
+	require.Equal(t, false, (*secondaryRequests)[5].Params["pausedManual"])
+}
+
+func TestFailbackPreChecks(t *testing.T) {
+	primary := getTestClient(t)
+	secondary := getTestRemoteClient(t)
+	mockReset := activateMock(t, primary, buildVolumesResponse(testPrimaryVolume(VolumeAccessPolicyReplicationTarget, ReplicationStatePausedMisconfigured)))
+	defer mockReset()
+	activateMock(t, secondary, buildVolumesResponse(testSecondaryVolume(VolumeAccessPolicyReplicationTarget, ReplicationStateActive)))
+
+	ctx := context.Background()
+	_, err := PlanFailback(ctx, primary, secondary, testVolumeId, FailbackOptions{})
+	require.Contains(t, err.Error(), "it should have been failed over")
+
+	activateMock(t, secondary, buildVolumesResponse(testSecondaryVolume(VolumeAccessPolicyReadWrite, ReplicationStatePausedMisconfigured)))
+	_, err = PlanFailback(ctx, primary, secondary, testVolumeId, FailbackOptions{})
+	require.Contains(t, err.Error(), "it can't be resynced")
+
+	plan, err := PlanFailback(ctx, primary, secondary, testVolumeId, FailbackOptions{Force: true})
+	require.Nil(t, err)
+	require.Len(t, plan.Steps, 5)
+}
+
+func TestReplicationPlanExecuteError(t *testing.T) {
+	plan := ReplicationPlan{Name: "Test"}
+	calls := 0
+	plan.add("first", func(ctx context.Context) error { calls++; return nil })
+	plan.add("second", func(ctx context.Context) error { calls++; return errors.New("boom") })
+	plan.add("third", func(ctx context.Context) error { calls++; return nil })
+
+	err := plan.Execute(context.Background(), nil)
+	require.EqualError(t, err, "Test step 2 (second): boom")
+	require.Equal(t, 2, calls)
+
+	plan = ReplicationPlan{Name: "Test"}
+	calls = 0
+	plan.addChecked("checked", func(ctx context.Context) error { return preCheckFailed("not ready") },
+		func(ctx context.Context) error { calls++; return nil })
+	err = plan.Execute(context.Background(), nil)
+	require.EqualError(t, err, "Test step 1 (checked) check: Replication pre-check failed : not ready")
+	require.Equal(t, 0, calls)
+}
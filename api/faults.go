@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+)
+
+const (
+	// Valid cluster fault severity values
+	FaultSeverityBestPractice = "bestPractice"
+	FaultSeverityWarning      = "warning"
+	FaultSeverityError        = "error"
+	FaultSeverityCritical     = "critical"
+)
+
+const (
+	// Valid cluster fault type values
+	FaultTypeCluster = "cluster"
+	FaultTypeNode    = "node"
+	FaultTypeDrive   = "drive"
+	FaultTypeService = "service"
+	FaultTypeVolume  = "volume"
+)
+
+const (
+	// Valid faultTypes values for ListClusterFaults and ClearClusterFaults
+	FaultTypesCurrent  = "current"
+	FaultTypesResolved = "resolved"
+	FaultTypesAll      = "all"
+)
+
+const (
+	// Kinds of FaultEvent emitted by a FaultWatcher
+	FaultEventNew      = "new"
+	FaultEventChanged  = "changed"
+	FaultEventResolved = "resolved"
+)
+
+const defaultFaultWatchInterval = time.Second * 30
+
+func (c *Client) ListClusterFaults(ctx context.Context, req ListClusterFaultsRequest) (result []ClusterFaultInfo, err error) {
+	lcfr := ListClusterFaultsResult{}
+	err = c.request(ctx, "ListClusterFaults", req, &lcfr)
+	result = lcfr.Faults
+	return result, err
+}
+
+// ClearClusterFaults removes resolved faults from the fault log. faultTypes defaults to resolved,
+// current faults can't be cleared.
+func (c *Client) ClearClusterFaults(ctx context.Context, faultTypes string) (err error) {
+	req := ClearClusterFaultsRequest{
+		FaultTypes: faultTypes,
+	}
+	return c.request(ctx, "ClearClusterFaults", req, nil)
+}
+
+type FaultEvent struct {
+	// One of FaultEventNew, FaultEventChanged or FaultEventResolved
+	Type string
+	// Latest state of the fault. For resolved events this is the last state seen before the fault
+	// disappeared from the current faults, with Resolved set.
+	Fault ClusterFaultInfo
+	// State of the fault at the previous poll, nil for new faults
+	Previous *ClusterFaultInfo
+}
+
+type FaultWatcherOptions struct {
+	// Delay between polls, 30 seconds by default
+	Interval time.Duration
+	// Only watch faults with one of these severities. All severities are watched when empty.
+	Severities []string
+	// Only watch faults of one of these types. All types are watched when empty.
+	Types []string
+	// Also watch best practice faults
+	BestPractices bool
+	// Called when a poll fails. The watcher keeps polling, failed polls emit no events.
+	OnError func(error)
+}
+
+// FaultWatcher polls the current cluster faults and reports the differences between polls. The
+// faults present at the first poll are reported as new.
+type FaultWatcher struct {
+	client *Client
+	opts   FaultWatcherOptions
+	known  map[int64]ClusterFaultInfo
+}
+
+func (c *Client) NewFaultWatcher(opts FaultWatcherOptions) *FaultWatcher {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultFaultWatchInterval
+	}
+	return &FaultWatcher{
+		client: c,
+		opts:   opts,
+		known:  map[int64]ClusterFaultInfo{},
+	}
+}
+
+// Watch polls in the background until ctx is done and sends the events on the returned channel,
+// which is closed once watching stops. A FaultWatcher must not be watched more than once at a time.
+func (w *FaultWatcher) Watch(ctx context.Context) <-chan FaultEvent {
+	events := make(chan FaultEvent)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(w.opts.Interval)
+		defer ticker.Stop()
+		for {
+			found, err := w.Poll(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if w.opts.OnError != nil {
+					w.opts.OnError(err)
+				}
+			}
+			for _, e := range found {
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// Poll lists the current faults once and returns the events since the previous poll, ordered by
+// ClusterFaultID
+func (w *FaultWatcher) Poll(ctx context.Context) (result []FaultEvent, err error) {
+	req := ListClusterFaultsRequest{
+		BestPractices: w.opts.BestPractices,
+		FaultTypes:    FaultTypesCurrent,
+	}
+	faults, err := w.client.ListClusterFaults(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[int64]ClusterFaultInfo{}
+	for _, f := range faults {
+		if !w.matches(f) {
+			continue
+		}
+		current[f.ClusterFaultID] = f
+		previous, ok := w.known[f.ClusterFaultID]
+		if !ok {
+			result = append(result, FaultEvent{Type: FaultEventNew, Fault: f})
+		} else if !reflect.DeepEqual(previous, f) {
+			result = append(result, FaultEvent{Type: FaultEventChanged, Fault: f, Previous: &previous})
+		}
+	}
+	for id, previous := range w.known {
+		if _, ok := current[id]; ok {
+			continue
+		}
+		resolved := previous
+		resolved.Resolved = true
+		previous := previous
+		result = append(result, FaultEvent{Type: FaultEventResolved, Fault: resolved, Previous: &previous})
+	}
+	w.known = current
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Fault.ClusterFaultID < result[j].Fault.ClusterFaultID
+	})
+	return result, nil
+}
+
+func (w *FaultWatcher) matches(f ClusterFaultInfo) bool {
+	return containsOrEmpty(w.opts.Severities, f.Severity) && containsOrEmpty(w.opts.Types, f.Type)
+}
+
+func containsOrEmpty(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
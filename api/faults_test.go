@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func testFault(id int64, severity string, faultType string, details string) map[string]interface{} {
+	return map[string]interface{}{
+		"clusterFaultID": id,
+		"severity":       severity,
+		"type":           faultType,
+		"code":           "driveFailed",
+		"details":        details,
+		"nodeID":         testNodeId,
+		"date":           "2021-05-24T15:18:14Z",
+	}
+}
+
+func buildFaultsResponse(faults ...map[string]interface{}) SFResponse {
+	return buildSFResponseWrapper(map[string]interface{}{"Faults": faults})
+}
+
+func TestListClusterFaults(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, buildFaultsResponse(testFault(1, FaultSeverityError, FaultTypeDrive, "Drive 31 failed")))
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ListClusterFaults(ctx, ListClusterFaultsRequest{FaultTypes: FaultTypesCurrent})
+	require.Nil(t, err)
+	require.Len(t, resp, 1)
+	require.Equal(t, int64(1), resp[0].ClusterFaultID)
+	require.Equal(t, FaultSeverityError, resp[0].Severity)
+	require.Equal(t, FaultTypesCurrent, (*requests)[0].Params["faultTypes"])
+}
+
+func TestClearClusterFaults(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, buildSFResponseWrapper(map[string]interface{}{}))
+	defer mockReset()
+
+	ctx := context.Background()
+	err := c.ClearClusterFaults(ctx, FaultTypesResolved)
+	require.Nil(t, err)
+	require.Equal(t, "ClearClusterFaults", (*requests)[0].Method)
+	require.Equal(t, FaultTypesResolved, (*requests)[0].Params["faultTypes"])
+}
+
+func TestFaultWatcherPoll(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildFaultsResponse(
+			testFault(2, FaultSeverityWarning, FaultTypeNode, "Node 1 is hot"),
+			testFault(1, FaultSeverityError, FaultTypeDrive, "Drive 31 failed"),
+			testFault(3, FaultSeverityError, FaultTypeService, "Service 4 is down"),
+		),
+		buildFaultsResponse(
+			testFault(2, FaultSeverityCritical, FaultTypeNode, "Node 1 is overheating"),
+			testFault(4, FaultSeverityError, FaultTypeDrive, "Drive 32 failed"),
+		),
+	)
+	defer mockReset()
+
+	w := c.NewFaultWatcher(FaultWatcherOptions{
+		Severities: []string{FaultSeverityWarning, FaultSeverityError, FaultSeverityCritical},
+		Types:      []string{FaultTypeNode, FaultTypeDrive},
+	})
+	ctx := context.Background()
+	events, err := w.Poll(ctx)
+	require.Nil(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, FaultEventNew, events[0].Type)
+	require.Equal(t, int64(1), events[0].Fault.ClusterFaultID)
+	require.Nil(t, events[0].Previous)
+	require.Equal(t, int64(2), events[1].Fault.ClusterFaultID)
+	require.Equal(t, FaultTypesCurrent, (*requests)[0].Params["faultTypes"])
+
+	events, err = w.Poll(ctx)
+	require.Nil(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, FaultEventResolved, events[0].Type)
+	require.Equal(t, int64(1), events[0].Fault.ClusterFaultID)
+	require.True(t, events[0].Fault.Resolved)
+	require.Equal(t, FaultEventChanged, events[1].Type)
+	require.Equal(t, FaultSeverityCritical, events[1].Fault.Severity)
+	require.Equal(t, FaultSeverityWarning, events[1].Previous.Severity)
+	require.Equal(t, FaultEventNew, events[2].Type)
+	require.Equal(t, int64(4), events[2].Fault.ClusterFaultID)
+
+	events, err = w.Poll(ctx)
+	require.Nil(t, err)
+	require.Len(t, events, 0)
+}
+
+func TestFaultWatcherWatch(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMockSequence(c,
+		buildSFResponseWrapper(map[string]interface{}{}),
+		buildFaultsResponse(testFault(1, FaultSeverityError, FaultTypeDrive, "Drive 31 failed")),
+	)
+	defer mockReset()
+
+	errs := make(chan error, 1)
+	w := c.NewFaultWatcher(FaultWatcherOptions{
+		Interval: time.Millisecond,
+		OnError:  func(err error) { errs <- err },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	events := w.Watch(ctx)
+
+	e := <-events
+	require.Equal(t, FaultEventNew, e.Type)
+	require.Equal(t, int64(1), e.Fault.ClusterFaultID)
+	cancel()
+	for range events {
+	}
+	require.Len(t, errs, 0)
+}
+
+func TestFaultWatcherOnError(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := SFResponse{
+		Error: SFAPIError{
+			Code:    500,
+			Name:    ErrInvalidParameter,
+			Message: "Invalid faultTypes",
+		},
+	}
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	errs := make(chan error, 1)
+	w := c.NewFaultWatcher(FaultWatcherOptions{
+		Interval: time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	events := w.Watch(ctx)
+
+	err := <-errs
+	cancel()
+	for range events {
+	}
+	require.NotNil(t, err)
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// CreateGroupSnapshot takes a crash consistent snapshot of all the given volumes at once
+func (c *Client) CreateGroupSnapshot(ctx context.Context, req CreateGroupSnapshotRequest) (result *GroupSnapshot, err error) {
+	cgsr := CreateGroupSnapshotResult{}
+	err = c.request(ctx, "CreateGroupSnapshot", req, &cgsr)
+	result = &cgsr.GroupSnapshot
+	return result, err
+}
+
+func (c *Client) ModifyGroupSnapshot(ctx context.Context, req ModifyGroupSnapshotRequest) (result *GroupSnapshot, err error) {
+	mgsr := ModifyGroupSnapshotResult{}
+	err = c.request(ctx, "ModifyGroupSnapshot", req, &mgsr)
+	result = &mgsr.GroupSnapshot
+	return result, err
+}
+
+// DeleteGroupSnapshot deletes a group snapshot. When saveMembers is true the member snapshots are
+// kept as individual snapshots of their volumes.
+func (c *Client) DeleteGroupSnapshot(ctx context.Context, id int64, saveMembers bool) error {
+	req := DeleteGroupSnapshotRequest{
+		GroupSnapshotID: id,
+		SaveMembers:     saveMembers,
+	}
+	return c.request(ctx, "DeleteGroupSnapshot", req, nil)
+}
+
+func (c *Client) ListGroupSnapshots(ctx context.Context, req ListGroupSnapshotsRequest) (result []GroupSnapshot, err error) {
+	lgsr := ListGroupSnapshotsResult{}
+	err = c.request(ctx, "ListGroupSnapshots", req, &lgsr)
+	result = lgsr.GroupSnapshots
+	return result, err
+}
+
+func (c *Client) GetGroupSnapshotByID(ctx context.Context, id int64) (result *GroupSnapshot, err error) {
+	req := ListGroupSnapshotsRequest{
+		GroupSnapshotID: id,
+	}
+	resp, err := c.ListGroupSnapshots(ctx, req)
+	if len(resp) > 0 {
+		result = &resp[0]
+	} else if err == nil {
+		err = BuildRequestError(ErrGroupSnapshotIDDoesNotExist, fmt.Sprintf("Group snapshot with the given id %d does not exist", id))
+	}
+	return result, err
+}
+
+// RollbackToGroupSnapshot rolls every member volume back to the group snapshot. When
+// SaveCurrentState is set the result holds the group snapshot of the pre-rollback state.
+func (c *Client) RollbackToGroupSnapshot(ctx context.Context, req RollbackToGroupSnapshotRequest) (result *RollbackToGroupSnapshotResult, err error) {
+	rgsr := RollbackToGroupSnapshotResult{}
+	err = c.request(ctx, "RollbackToGroupSnapshot", req, &rgsr)
+	if err != nil {
+		return nil, err
+	}
+	result = &rgsr
+	return result, err
+}
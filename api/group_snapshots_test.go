@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const testGroupSnapshotName = "solidfire-sdk-group-snapshot-test"
+const testGroupSnapshotId int64 = 31
+const testGroupSnapshotUUID = "6f2b7d7e-52d4-4f3c-9b8a-5a7c7e0c2d11"
+
+var testGroupSnapshot = map[string]interface{}{
+	"groupSnapshotID":   testGroupSnapshotId,
+	"groupSnapshotUUID": testGroupSnapshotUUID,
+	"name":              testGroupSnapshotName,
+	"createTime":        "2021-05-24T15:18:14Z",
+	"status":            "done",
+	"attributes":        map[string]interface{}{},
+	"members": []map[string]interface{}{
+		{"volumeID": testVolumeId, "snapshotID": 9501, "snapshotUUID": "a1", "checksum": "0x0"},
+		{"volumeID": testSnapshotVolumeId, "snapshotID": 9502, "snapshotUUID": "a2", "checksum": "0x0"},
+	},
+}
+
+func TestCreateGroupSnapshot(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{
+		"GroupSnapshot":   testGroupSnapshot,
+		"GroupSnapshotID": testGroupSnapshotId,
+		"Members":         testGroupSnapshot["members"],
+	})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := CreateGroupSnapshotRequest{
+		Volumes: []int64{testVolumeId, testSnapshotVolumeId},
+		Name:    testGroupSnapshotName,
+	}
+	resp, err := c.CreateGroupSnapshot(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, testGroupSnapshotId, resp.GroupSnapshotID)
+	require.Equal(t, testGroupSnapshotName, resp.Name)
+	require.Len(t, resp.Members, 2)
+	require.Equal(t, testVolumeId, resp.Members[0].VolumeID)
+	require.Equal(t, int64(9501), resp.Members[0].SnapshotID)
+}
+
+func TestModifyGroupSnapshot(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"GroupSnapshot": testGroupSnapshot})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := ModifyGroupSnapshotRequest{
+		GroupSnapshotID: testGroupSnapshotId,
+		ExpirationTime:  "2021-06-24T15:18:14Z",
+	}
+	resp, err := c.ModifyGroupSnapshot(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, testGroupSnapshotId, resp.GroupSnapshotID)
+	require.Equal(t, "2021-06-24T15:18:14Z", (*requests)[0].Params["expirationTime"])
+}
+
+func TestDeleteGroupSnapshot(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	err := c.DeleteGroupSnapshot(ctx, testGroupSnapshotId, true)
+	require.Nil(t, err)
+	require.Equal(t, "DeleteGroupSnapshot", (*requests)[0].Method)
+	require.Equal(t, float64(testGroupSnapshotId), (*requests)[0].Params["groupSnapshotID"])
+	require.Equal(t, true, (*requests)[0].Params["saveMembers"])
+}
+
+func TestListGroupSnapshots(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"GroupSnapshots": []map[string]interface{}{testGroupSnapshot}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ListGroupSnapshots(ctx, ListGroupSnapshotsRequest{Volumes: []int64{testVolumeId}})
+	require.Nil(t, err)
+	require.Len(t, resp, 1)
+	require.Equal(t, testGroupSnapshotUUID, resp[0].GroupSnapshotUUID)
+}
+
+func TestGetGroupSnapshotByID(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"GroupSnapshots": []map[string]interface{}{testGroupSnapshot}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetGroupSnapshotByID(ctx, testGroupSnapshotId)
+	require.Nil(t, err)
+	require.Equal(t, testGroupSnapshotId, resp.GroupSnapshotID)
+	require.Equal(t, testGroupSnapshotName, resp.Name)
+}
+
+func TestGetGroupSnapshotByIDError(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"GroupSnapshots": []map[string]interface{}{}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	_, err := c.GetGroupSnapshotByID(ctx, testGroupSnapshotId)
+	require.NotNil(t, err)
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrGroupSnapshotIDDoesNotExist, reqErr.Name)
+}
+
+func TestRollbackToGroupSnapshot(t *testing.T) {
+	c := getTestClient(t)
+	saved := make(map[string]interface{})
+	for k, v := range testGroupSnapshot {
+		saved[k] = v
+	}
+	saved["groupSnapshotID"] = testGroupSnapshotId + 1
+	saved["name"] = "pre-rollback"
+	mockResp := buildSFResponseWrapper(map[string]interface{}{
+		"GroupSnapshot":   saved,
+		"GroupSnapshotID": testGroupSnapshotId + 1,
+		"Members":         saved["members"],
+	})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := RollbackToGroupSnapshotRequest{
+		GroupSnapshotID:  testGroupSnapshotId,
+		SaveCurrentState: true,
+		Name:             "pre-rollback",
+	}
+	resp, err := c.RollbackToGroupSnapshot(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, testGroupSnapshotId+1, resp.GroupSnapshotID)
+	require.Equal(t, "pre-rollback", resp.GroupSnapshot.Name)
+	require.Len(t, resp.Members, 2)
+	require.Equal(t, true, (*requests)[0].Params["saveCurrentState"])
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// Number of items requested per page by the Iterate methods when the request has no Limit
+const defaultPageSize = 500
+
+// pager tracks the position of an iterator in a paginated List call. Pages are requested from the
+// ID following the last item of the previous page, and a short page ends the iteration.
+type pager struct {
+	ctx      context.Context
+	pageSize int64
+	start    int64
+	done     bool
+	err      error
+}
+
+func newPager(ctx context.Context, start int64, limit int64) pager {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	return pager{ctx: ctx, pageSize: limit, start: start}
+}
+
+// checkContext stops the iteration once the context is done, even with items left in the page
+func (p *pager) checkContext() bool {
+	if p.err == nil && p.ctx.Err() != nil {
+		p.err = errors.Wrap(p.ctx.Err(), "iterating over pages")
+	}
+	return p.err == nil
+}
+
+// next fetches the next page, fetch returns the number of items in the page and the ID of the last one
+func (p *pager) next(fetch func(ctx context.Context, start int64, limit int64) (int, int64, error)) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	n, lastID, err := fetch(p.ctx, p.start, p.pageSize)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	if int64(n) < p.pageSize {
+		p.done = true
+	}
+	p.start = lastID + 1
+	return n > 0
+}
+
+// VolumeIterator walks the volumes returned by a paginated volume List call, fetching a page at a
+// time as Next is called:
+//
+//	it := c.IterateVolumes(ctx, ListVolumesRequest{Limit: 1000})
+//	for it.Next() {
+//		fmt.Println(it.Volume().Name)
+//	}
+//	err := it.Err()
+type VolumeIterator struct {
+	p     pager
+	fetch func(ctx context.Context, start int64, limit int64) ([]Volume, error)
+	page  []Volume
+	pos   int
+}
+
+// Next advances to the next volume, returning false once there are none left or on error
+func (it *VolumeIterator) Next() bool {
+	if !it.p.checkContext() {
+		return false
+	}
+	if it.pos+1 < len(it.page) {
+		it.pos++
+		return true
+	}
+	return it.p.next(func(ctx context.Context, start int64, limit int64) (int, int64, error) {
+		page, err := it.fetch(ctx, start, limit)
+		it.page, it.pos = page, 0
+		if err != nil || len(page) == 0 {
+			return 0, 0, err
+		}
+		return len(page), page[len(page)-1].VolumeID, nil
+	})
+}
+
+// Volume returns the current volume, only valid after Next returned true
+func (it *VolumeIterator) Volume() Volume {
+	return it.page[it.pos]
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *VolumeIterator) Err() error {
+	return it.p.err
+}
+
+// IterateVolumes pages through ListVolumes starting at req.StartVolumeID, req.Limit volumes at a time
+func (c *Client) IterateVolumes(ctx context.Context, req ListVolumesRequest) *VolumeIterator {
+	return &VolumeIterator{
+		p: newPager(ctx, req.StartVolumeID, req.Limit),
+		fetch: func(ctx context.Context, start int64, limit int64) ([]Volume, error) {
+			req.StartVolumeID, req.Limit = start, limit
+			return c.ListVolumes(ctx, req)
+		},
+	}
+}
+
+// IterateVolumesForAccount pages through ListVolumesForAccount starting at req.StartVolumeID,
+// req.Limit volumes at a time
+func (c *Client) IterateVolumesForAccount(ctx context.Context, req ListVolumesForAccountRequest) *VolumeIterator {
+	return &VolumeIterator{
+		p: newPager(ctx, req.StartVolumeID, req.Limit),
+		fetch: func(ctx context.Context, start int64, limit int64) ([]Volume, error) {
+			req.StartVolumeID, req.Limit = start, limit
+			return c.ListVolumesForAccount(ctx, req)
+		},
+	}
+}
+
+// IterateActivePairedVolumes pages through ListActivePairedVolumes starting at req.StartVolumeID,
+// req.Limit volumes at a time
+func (c *Client) IterateActivePairedVolumes(ctx context.Context, req ListActivePairedVolumesRequest) *VolumeIterator {
+	return &VolumeIterator{
+		p: newPager(ctx, req.StartVolumeID, req.Limit),
+		fetch: func(ctx context.Context, start int64, limit int64) ([]Volume, error) {
+			req.StartVolumeID, req.Limit = start, limit
+			return c.ListActivePairedVolumes(ctx, req)
+		},
+	}
+}
+
+// InitiatorIterator walks the initiators returned by ListInitiators like VolumeIterator
+type InitiatorIterator struct {
+	p     pager
+	fetch func(ctx context.Context, start int64, limit int64) ([]Initiator, error)
+	page  []Initiator
+	pos   int
+}
+
+func (it *InitiatorIterator) Next() bool {
+	if !it.p.checkContext() {
+		return false
+	}
+	if it.pos+1 < len(it.page) {
+		it.pos++
+		return true
+	}
+	return it.p.next(func(ctx context.Context, start int64, limit int64) (int, int64, error) {
+		page, err := it.fetch(ctx, start, limit)
+		it.page, it.pos = page, 0
+		if err != nil || len(page) == 0 {
+			return 0, 0, err
+		}
+		return len(page), page[len(page)-1].InitiatorID, nil
+	})
+}
+
+func (it *InitiatorIterator) Initiator() Initiator {
+	return it.page[it.pos]
+}
+
+func (it *InitiatorIterator) Err() error {
+	return it.p.err
+}
+
+// IterateInitiators pages through ListInitiators starting at req.StartInitiatorID, req.Limit
+// initiators at a time
+func (c *Client) IterateInitiators(ctx context.Context, req ListInitiatorsRequest) *InitiatorIterator {
+	return &InitiatorIterator{
+		p: newPager(ctx, req.StartInitiatorID, req.Limit),
+		fetch: func(ctx context.Context, start int64, limit int64) ([]Initiator, error) {
+			req.StartInitiatorID, req.Limit = start, limit
+			return c.ListInitiators(ctx, req)
+		},
+	}
+}
+
+// VolumeAccessGroupIterator walks the groups returned by ListVolumeAccessGroups like VolumeIterator
+type VolumeAccessGroupIterator struct {
+	p     pager
+	fetch func(ctx context.Context, start int64, limit int64) ([]VolumeAccessGroup, error)
+	page  []VolumeAccessGroup
+	pos   int
+}
+
+func (it *VolumeAccessGroupIterator) Next() bool {
+	if !it.p.checkContext() {
+		return false
+	}
+	if it.pos+1 < len(it.page) {
+		it.pos++
+		return true
+	}
+	return it.p.next(func(ctx context.Context, start int64, limit int64) (int, int64, error) {
+		page, err := it.fetch(ctx, start, limit)
+		it.page, it.pos = page, 0
+		if err != nil || len(page) == 0 {
+			return 0, 0, err
+		}
+		return len(page), page[len(page)-1].VolumeAccessGroupID, nil
+	})
+}
+
+func (it *VolumeAccessGroupIterator) VolumeAccessGroup() VolumeAccessGroup {
+	return it.page[it.pos]
+}
+
+func (it *VolumeAccessGroupIterator) Err() error {
+	return it.p.err
+}
+
+// IterateVolumeAccessGroups pages through ListVolumeAccessGroups starting at
+// req.StartVolumeAccessGroupID, req.Limit groups at a time
+func (c *Client) IterateVolumeAccessGroups(ctx context.Context, req ListVolumeAccessGroupsRequest) *VolumeAccessGroupIterator {
+	return &VolumeAccessGroupIterator{
+		p: newPager(ctx, req.StartVolumeAccessGroupID, req.Limit),
+		fetch: func(ctx context.Context, start int64, limit int64) ([]VolumeAccessGroup, error) {
+			req.StartVolumeAccessGroupID, req.Limit = start, limit
+			return c.ListVolumeAccessGroups(ctx, req)
+		},
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func testVolumePage(ids ...int64) SFResponse {
+	volumes := []map[string]interface{}{}
+	for _, id := range ids {
+		volumes = append(volumes, testReplicationVolume(map[string]interface{}{"volumeID": id}))
+	}
+	return buildVolumesResponse(volumes...)
+}
+
+func TestIterateVolumes(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		testVolumePage(1, 2),
+		testVolumePage(4, 7),
+		testVolumePage(9),
+	)
+	defer mockReset()
+
+	it := c.IterateVolumes(context.Background(), ListVolumesRequest{Limit: 2, VolumeStatus: "active"})
+	require.True(t, it.Next())
+	require.Equal(t, int64(1), it.Volume().VolumeID)
+	// Pages are only fetched when needed
+	require.Len(t, *requests, 1)
+
+	ids := []int64{it.Volume().VolumeID}
+	for it.Next() {
+		ids = append(ids, it.Volume().VolumeID)
+	}
+	require.Nil(t, it.Err())
+	require.Equal(t, []int64{1, 2, 4, 7, 9}, ids)
+	require.Len(t, *requests, 3)
+	require.NotContains(t, (*requests)[0].Params, "startVolumeID")
+	require.Equal(t, float64(3), (*requests)[1].Params["startVolumeID"])
+	require.Equal(t, float64(8), (*requests)[2].Params["startVolumeID"])
+	for _, r := range *requests {
+		require.Equal(t, float64(2), r.Params["limit"])
+		require.Equal(t, "active", r.Params["volumeStatus"])
+	}
+	require.False(t, it.Next())
+}
+
+func TestIterateVolumesFullLastPage(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, testVolumePage(1, 2), testVolumePage())
+	defer mockReset()
+
+	it := c.IterateVolumesForAccount(context.Background(), ListVolumesForAccountRequest{AccountID: testAccountId, Limit: 2})
+	count := 0
+	for it.Next() {
+		count++
+	}
+	require.Nil(t, it.Err())
+	require.Equal(t, 2, count)
+	require.Equal(t, []string{"ListVolumesForAccount", "ListVolumesForAccount"}, requestMethods(*requests))
+	require.Equal(t, float64(testAccountId), (*requests)[1].Params["accountID"])
+}
+
+func TestIterateVolumesDefaultPageSize(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, testVolumePage(10))
+	defer mockReset()
+
+	it := c.IterateActivePairedVolumes(context.Background(), ListActivePairedVolumesRequest{StartVolumeID: 10})
+	require.True(t, it.Next())
+	require.False(t, it.Next())
+	require.Nil(t, it.Err())
+	require.Equal(t, "ListActivePairedVolumes", (*requests)[0].Method)
+	require.Equal(t, float64(defaultPageSize), (*requests)[0].Params["limit"])
+	require.Equal(t, float64(10), (*requests)[0].Params["startVolumeID"])
+}
+
+func TestIterateVolumesCancel(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, testVolumePage(1, 2))
+	defer mockReset()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := c.IterateVolumes(ctx, ListVolumesRequest{Limit: 2})
+	require.True(t, it.Next())
+	cancel()
+	require.False(t, it.Next())
+	require.True(t, errors.Is(it.Err(), context.Canceled))
+	require.Len(t, *requests, 1)
+}
+
+func TestIterateVolumesError(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMock(t, c, SFResponse{Error: SFAPIError{Code: 500, Name: ErrInvalidParameter, Message: "invalid"}})
+	defer mockReset()
+
+	it := c.IterateVolumes(context.Background(), ListVolumesRequest{})
+	require.False(t, it.Next())
+	require.Equal(t, ErrInvalidParameter, it.Err().(*RequestError).Name)
+	require.False(t, it.Next())
+}
+
+func TestIterateInitiators(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildSFResponseWrapper(map[string]interface{}{"Initiators": []map[string]interface{}{
+			{"initiatorID": 1, "initiatorName": testInitiatorName},
+			{"initiatorID": 5, "initiatorName": "iqn.1993-08.org.debian:01:2"},
+		}}),
+		buildSFResponseWrapper(map[string]interface{}{"Initiators": []map[string]interface{}{}}),
+	)
+	defer mockReset()
+
+	it := c.IterateInitiators(context.Background(), ListInitiatorsRequest{Limit: 2})
+	names := []string{}
+	for it.Next() {
+		names = append(names, it.Initiator().InitiatorName)
+	}
+	require.Nil(t, it.Err())
+	require.Equal(t, []string{testInitiatorName, "iqn.1993-08.org.debian:01:2"}, names)
+	require.Equal(t, float64(6), (*requests)[1].Params["startInitiatorID"])
+}
+
+func TestIterateVolumeAccessGroups(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildSFResponseWrapper(map[string]interface{}{"VolumeAccessGroups": []map[string]interface{}{testVolumeAccessGroup}}),
+	)
+	defer mockReset()
+
+	it := c.IterateVolumeAccessGroups(context.Background(), ListVolumeAccessGroupsRequest{Limit: 10})
+	require.True(t, it.Next())
+	require.Equal(t, testVolumeAccessGroupId, it.VolumeAccessGroup().VolumeAccessGroupID)
+	require.False(t, it.Next())
+	require.Nil(t, it.Err())
+	require.Len(t, *requests, 1)
+}
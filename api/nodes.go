@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+)
+
+// ListAllNodes returns the active, pending and pending active nodes of the cluster
+func (c *Client) ListAllNodes(ctx context.Context) (result *ListAllNodesResult, err error) {
+	lanr := ListAllNodesResult{}
+	err = c.request(ctx, "ListAllNodes", struct{}{}, &lanr)
+	if err != nil {
+		return nil, err
+	}
+	result = &lanr
+	return result, err
+}
+
+func (c *Client) ListActiveNodes(ctx context.Context) (result []Node, err error) {
+	lanr := ListActiveNodesResult{}
+	err = c.request(ctx, "ListActiveNodes", struct{}{}, &lanr)
+	result = lanr.Nodes
+	return result, err
+}
+
+// ListPendingNodes lists the nodes that are configured to join the cluster but haven't been added yet
+func (c *Client) ListPendingNodes(ctx context.Context) (result []PendingNode, err error) {
+	lpnr := ListPendingNodesResult{}
+	err = c.request(ctx, "ListPendingNodes", struct{}{}, &lpnr)
+	result = lpnr.PendingNodes
+	return result, err
+}
+
+// ListPendingActiveNodes lists the nodes that are being added to the cluster
+func (c *Client) ListPendingActiveNodes(ctx context.Context) (result []PendingActiveNode, err error) {
+	lpanr := ListPendingActiveNodesResult{}
+	err = c.request(ctx, "ListPendingActiveNodes", struct{}{}, &lpanr)
+	result = lpanr.PendingActiveNodes
+	return result, err
+}
+
+// AddNodes adds pending nodes to the cluster. Each node joins asynchronously, wait for its
+// AsyncHandle to know when it has been added.
+func (c *Client) AddNodes(ctx context.Context, req AddNodesRequest) (result []AddedNode, err error) {
+	anr := AddNodesResult{}
+	err = c.request(ctx, "AddNodes", req, &anr)
+	if err != nil {
+		return nil, err
+	}
+	result = anr.Nodes
+	return result, nil
+}
+
+// RemoveNodes removes active nodes from the cluster. Their drives must be removed first.
+func (c *Client) RemoveNodes(ctx context.Context, ids []int64) (err error) {
+	req := RemoveNodesRequest{
+		Nodes: ids,
+	}
+	return c.request(ctx, "RemoveNodes", req, nil)
+}
+
+func (c *Client) GetNodeStats(ctx context.Context, id int64) (result *NodeStatsInfo, err error) {
+	req := GetNodeStatsRequest{
+		NodeID: id,
+	}
+	gnsr := GetNodeStatsResult{}
+	err = c.request(ctx, "GetNodeStats", req, &gnsr)
+	if err != nil {
+		return nil, err
+	}
+	result = &gnsr.NodeStats
+	return result, err
+}
+
+func (c *Client) ListNodeStats(ctx context.Context) (result []NodeStatsInfo, err error) {
+	lnsr := ListNodeStatsResult{}
+	err = c.request(ctx, "ListNodeStats", struct{}{}, &lnsr)
+	result = lnsr.NodeStats.Nodes
+	return result, err
+}
+
+// GetNodeHardwareInfo returns the hardware details of a node. The layout depends on the node platform.
+func (c *Client) GetNodeHardwareInfo(ctx context.Context, id int64) (result interface{}, err error) {
+	req := GetNodeHardwareInfoRequest{
+		NodeID: id,
+	}
+	gnhir := GetNodeHardwareInfoResult{}
+	err = c.request(ctx, "GetNodeHardwareInfo", req, &gnhir)
+	result = gnhir.NodeHardwareInfo
+	return result, err
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testNodeId int64 = 1
+const testPendingNodeId int64 = 7
+
+var testNode = map[string]interface{}{
+	"nodeID":          testNodeId,
+	"name":            "sf-node-1",
+	"mip":             "10.0.0.11",
+	"cip":             "10.0.2.11",
+	"sip":             "10.0.1.11",
+	"softwareVersion": "12.3.0.958",
+	"uuid":            "4c4c4544-0000-0000-0000-000000000001",
+	"platformInfo":    map[string]interface{}{"nodeType": "SF4805"},
+}
+
+var testPendingNode = map[string]interface{}{
+	"pendingNodeID":   testPendingNodeId,
+	"assignedNodeID":  0,
+	"name":            "sf-node-7",
+	"compatible":      true,
+	"mip":             "10.0.0.17",
+	"cip":             "10.0.2.17",
+	"sip":             "10.0.1.17",
+	"softwareVersion": "12.3.0.958",
+}
+
+func TestListAllNodes(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{
+		"Nodes":        []map[string]interface{}{testNode},
+		"PendingNodes": []map[string]interface{}{testPendingNode},
+	})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ListAllNodes(ctx)
+	require.Nil(t, err)
+	require.Len(t, resp.Nodes, 1)
+	require.Equal(t, "sf-node-1", resp.Nodes[0].Name)
+	require.Len(t, resp.PendingNodes, 1)
+	require.Equal(t, testPendingNodeId, resp.PendingNodes[0].PendingNodeID)
+}
+
+func TestListActiveNodes(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"Nodes": []map[string]interface{}{testNode}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ListActiveNodes(ctx)
+	require.Nil(t, err)
+	require.Len(t, resp, 1)
+	require.Equal(t, testNodeId, resp[0].NodeID)
+	require.Equal(t, "10.0.0.11", resp[0].Mip)
+}
+
+func TestListPendingNodes(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"PendingNodes": []map[string]interface{}{testPendingNode}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ListPendingNodes(ctx)
+	require.Nil(t, err)
+	require.Len(t, resp, 1)
+	require.True(t, resp[0].Compatible)
+}
+
+func TestListPendingActiveNodes(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"PendingActiveNodes": []map[string]interface{}{
+		{"pendingNodeID": testPendingNodeId, "assignedNodeID": 8, "asyncHandle": 21, "activeNodeKey": "key"},
+	}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ListPendingActiveNodes(ctx)
+	require.Nil(t, err)
+	require.Len(t, resp, 1)
+	require.Equal(t, int64(8), resp[0].AssignedNodeID)
+	require.Equal(t, AsyncResultID(21), resp[0].AsyncHandle)
+}
+
+func TestAddNodes(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{
+		"AutoInstall": true,
+		"Nodes": []map[string]interface{}{
+			{"pendingNodeID": testPendingNodeId, "assignedNodeID": 8, "asyncHandle": 21, "activeNodeKey": "key1"},
+			{"pendingNodeID": testPendingNodeId + 1, "assignedNodeID": 9, "asyncHandle": 22, "activeNodeKey": "key2"},
+		},
+	})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := AddNodesRequest{
+		PendingNodes: []int64{testPendingNodeId, testPendingNodeId + 1},
+		AutoInstall:  true,
+	}
+	nodes, err := c.AddNodes(ctx, req)
+	require.Nil(t, err)
+	require.Len(t, nodes, 2)
+	require.Equal(t, int64(9), nodes[1].AssignedNodeID)
+	require.Equal(t, AsyncResultID(21), nodes[0].AsyncHandle)
+	require.Equal(t, AsyncResultID(22), nodes[1].AsyncHandle)
+	require.Equal(t, []interface{}{float64(testPendingNodeId), float64(testPendingNodeId + 1)}, (*requests)[0].Params["pendingNodes"])
+}
+
+func TestRemoveNodes(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	err := c.RemoveNodes(ctx, []int64{testNodeId})
+	require.Nil(t, err)
+	require.Equal(t, "RemoveNodes", (*requests)[0].Method)
+	require.Equal(t, []interface{}{float64(testNodeId)}, (*requests)[0].Params["nodes"])
+}
+
+func TestGetNodeStats(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"NodeStats": map[string]interface{}{
+		"nodeID":     testNodeId,
+		"cpu":        12,
+		"usedMemory": 1024,
+	}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetNodeStats(ctx, testNodeId)
+	require.Nil(t, err)
+	require.Equal(t, testNodeId, resp.NodeID)
+	require.Equal(t, int64(12), resp.Cpu)
+}
+
+func TestListNodeStats(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"NodeStats": map[string]interface{}{
+		"nodes": []map[string]interface{}{
+			{"nodeID": 1, "cpu": 12},
+			{"nodeID": 2, "cpu": 30},
+		},
+	}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ListNodeStats(ctx)
+	require.Nil(t, err)
+	require.Len(t, resp, 2)
+	require.Equal(t, int64(30), resp[1].Cpu)
+}
+
+func TestGetNodeHardwareInfo(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"NodeHardwareInfo": map[string]interface{}{
+		"bus": map[string]interface{}{"core_DMI:0200": map[string]interface{}{"vendor": "Dell"}},
+	}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetNodeHardwareInfo(ctx, testNodeId)
+	require.Nil(t, err)
+	info, ok := resp.(map[string]interface{})
+	require.True(t, ok)
+	require.Contains(t, info, "bus")
+}
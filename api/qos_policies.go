@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// QoS returns the settable part of the volume QoS, the curve is fixed by the cluster
+func (q VolumeQOS) QoS() QoS {
+	return QoS{
+		MinIOPS:   q.MinIOPS,
+		MaxIOPS:   q.MaxIOPS,
+		BurstIOPS: q.BurstIOPS,
+		BurstTime: q.BurstTime,
+	}
+}
+
+func (c *Client) CreateQoSPolicy(ctx context.Context, name string, qos QoS) (result *QoSPolicy, err error) {
+	req := CreateQoSPolicyRequest{
+		Name: name,
+		Qos:  qos,
+	}
+	cqpr := CreateQoSPolicyResult{}
+	err = c.request(ctx, "CreateQoSPolicy", req, &cqpr)
+	if err != nil {
+		return nil, err
+	}
+	result = &cqpr.QoSPolicy
+	return result, err
+}
+
+// ModifyQoSPolicy changes the name or QoS of a policy, the QoS of every volume using the policy
+// changes with it
+func (c *Client) ModifyQoSPolicy(ctx context.Context, req ModifyQoSPolicyRequest) (result *QoSPolicy, err error) {
+	mqpr := ModifyQoSPolicyResult{}
+	err = c.request(ctx, "ModifyQoSPolicy", req, &mqpr)
+	if err != nil {
+		return nil, err
+	}
+	result = &mqpr.QoSPolicy
+	return result, err
+}
+
+func (c *Client) ListQoSPolicies(ctx context.Context) (result []QoSPolicy, err error) {
+	lqpr := ListQoSPoliciesResult{}
+	err = c.request(ctx, "ListQoSPolicies", struct{}{}, &lqpr)
+	result = lqpr.QoSPolicies
+	return result, err
+}
+
+func (c *Client) GetQoSPolicy(ctx context.Context, id int64) (result *QoSPolicy, err error) {
+	req := GetQoSPolicyRequest{
+		QoSPolicyID: id,
+	}
+	gqpr := GetQoSPolicyResult{}
+	err = c.request(ctx, "GetQoSPolicy", req, &gqpr)
+	if err != nil {
+		return nil, err
+	}
+	result = &gqpr.QoSPolicy
+	return result, err
+}
+
+func (c *Client) GetQoSPolicyByName(ctx context.Context, name string) (result *QoSPolicy, err error) {
+	policies, err := c.ListQoSPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range policies {
+		if policies[i].Name == name {
+			return &policies[i], nil
+		}
+	}
+	return nil, &ResourceNotFoundError{
+		Name:    ErrQoSPolicyDoesNotExist,
+		Message: fmt.Sprintf("QoS policy with the given name %s does not exist", name),
+	}
+}
+
+// DeleteQoSPolicy deletes a policy. Volumes using it keep its QoS but are no longer associated with it.
+func (c *Client) DeleteQoSPolicy(ctx context.Context, id int64) (err error) {
+	req := DeleteQoSPolicyRequest{
+		QoSPolicyID: id,
+	}
+	return c.request(ctx, "DeleteQoSPolicy", req, nil)
+}
+
+// EnsureQoSPolicy creates the named policy with the given QoS, or updates the QoS of the existing
+// policy with that name if it differs. Fields left zero in qos get the cluster defaults on creation
+// and are otherwise left as they are.
+func (c *Client) EnsureQoSPolicy(ctx context.Context, name string, qos QoS) (result *QoSPolicy, err error) {
+	policy, err := c.GetQoSPolicyByName(ctx, name)
+	if err != nil {
+		var notFound *ResourceNotFoundError
+		if errors.As(err, &notFound) && notFound.Name == ErrQoSPolicyDoesNotExist {
+			return c.CreateQoSPolicy(ctx, name, qos)
+		}
+		return nil, err
+	}
+	if qosMatches(policy.Qos.QoS(), qos) {
+		return policy, nil
+	}
+	req := ModifyQoSPolicyRequest{
+		QoSPolicyID: policy.QoSPolicyID,
+		Qos:         qos,
+	}
+	return c.ModifyQoSPolicy(ctx, req)
+}
+
+// qosMatches reports whether current has the values of the non-zero fields of want
+func qosMatches(current QoS, want QoS) bool {
+	return (want.MinIOPS == 0 || want.MinIOPS == current.MinIOPS) &&
+		(want.MaxIOPS == 0 || want.MaxIOPS == current.MaxIOPS) &&
+		(want.BurstIOPS == 0 || want.BurstIOPS == current.BurstIOPS) &&
+		(want.BurstTime == 0 || want.BurstTime == current.BurstTime)
+}
+
+// GetDefaultQoS returns the QoS given to volumes created without one
+func (c *Client) GetDefaultQoS(ctx context.Context) (result *VolumeQOS, err error) {
+	gdqr := VolumeQOS{}
+	err = c.request(ctx, "GetDefaultQoS", struct{}{}, &gdqr)
+	if err != nil {
+		return nil, err
+	}
+	result = &gdqr
+	return result, err
+}
+
+func (c *Client) SetDefaultQoS(ctx context.Context, req SetDefaultQoSRequest) (result *SetDefaultQoSResult, err error) {
+	sdqr := SetDefaultQoSResult{}
+	err = c.request(ctx, "SetDefaultQoS", req, &sdqr)
+	if err != nil {
+		return nil, err
+	}
+	result = &sdqr
+	return result, err
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const testQoSPolicyId int64 = 2
+
+func testQoSPolicy(name string, minIOPS int64) map[string]interface{} {
+	return map[string]interface{}{
+		"qosPolicyID": testQoSPolicyId,
+		"name":        name,
+		"qos": map[string]interface{}{
+			"minIOPS":   minIOPS,
+			"maxIOPS":   15000,
+			"burstIOPS": 15000,
+			"burstTime": 60,
+			"curve":     map[string]float64{"4096": 100},
+		},
+		"volumeIDs": []int64{testVolumeId},
+	}
+}
+
+func TestCreateQoSPolicy(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildSFResponseWrapper(map[string]interface{}{"QoSPolicy": testQoSPolicy("gold", 1000)}))
+	defer mockReset()
+
+	ctx := context.Background()
+	policy, err := c.CreateQoSPolicy(ctx, "gold", QoS{MinIOPS: 1000, MaxIOPS: 15000, BurstIOPS: 15000})
+	require.Nil(t, err)
+	require.Equal(t, testQoSPolicyId, policy.QoSPolicyID)
+	require.Equal(t, "gold", policy.Name)
+	require.Equal(t, int64(1000), policy.Qos.MinIOPS)
+	require.Equal(t, []int64{testVolumeId}, policy.VolumeIDs)
+	require.Equal(t, "gold", (*requests)[0].Params["name"])
+	qos := (*requests)[0].Params["qos"].(map[string]interface{})
+	require.Equal(t, float64(1000), qos["minIOPS"])
+	require.NotContains(t, qos, "burstTime")
+}
+
+func TestGetQoSPolicy(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMock(t, c, buildSFResponseWrapper(map[string]interface{}{"QoSPolicy": testQoSPolicy("gold", 1000)}))
+	defer mockReset()
+
+	policy, err := c.GetQoSPolicy(context.Background(), testQoSPolicyId)
+	require.Nil(t, err)
+	require.Equal(t, "gold", policy.Name)
+	require.Equal(t, float64(100), policy.Qos.Curve["4096"])
+
+	activateMock(t, c, SFResponse{Error: SFAPIError{Code: 500, Name: ErrQoSPolicyDoesNotExist, Message: "QoS policy does not exist"}})
+	_, err = c.GetQoSPolicy(context.Background(), 1)
+	var notFound *ResourceNotFoundError
+	require.True(t, errors.As(err, &notFound))
+}
+
+func TestGetQoSPolicyByName(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMock(t, c, buildSFResponseWrapper(map[string]interface{}{"QoSPolicies": []map[string]interface{}{
+		testQoSPolicy("gold", 1000),
+		testQoSPolicy("silver", 500),
+	}}))
+	defer mockReset()
+
+	ctx := context.Background()
+	policy, err := c.GetQoSPolicyByName(ctx, "silver")
+	require.Nil(t, err)
+	require.Equal(t, int64(500), policy.Qos.MinIOPS)
+
+	_, err = c.GetQoSPolicyByName(ctx, "bronze")
+	require.Equal(t, ErrQoSPolicyDoesNotExist, err.(*ResourceNotFoundError).Name)
+}
+
+func TestEnsureQoSPolicy(t *testing.T) {
+	c := getTestClient(t)
+	gold := QoS{MinIOPS: 1000, MaxIOPS: 15000, BurstIOPS: 15000, BurstTime: 60}
+	listResp := buildSFResponseWrapper(map[string]interface{}{"QoSPolicies": []map[string]interface{}{testQoSPolicy("gold", 1000)}})
+	requests, mockReset := activateRecordingMock(c, listResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	_, err := c.EnsureQoSPolicy(ctx, "gold", gold)
+	require.Nil(t, err)
+	require.Equal(t, []string{"ListQoSPolicies"}, requestMethods(*requests))
+
+	// Fields left zero don't count as a difference
+	requests, _ = activateRecordingMock(c, listResp)
+	_, err = c.EnsureQoSPolicy(ctx, "gold", QoS{MinIOPS: 1000, MaxIOPS: 15000})
+	require.Nil(t, err)
+	require.Equal(t, []string{"ListQoSPolicies"}, requestMethods(*requests))
+
+	requests, _ = activateRecordingMock(c, listResp,
+		buildSFResponseWrapper(map[string]interface{}{"QoSPolicy": testQoSPolicy("gold", 2000)}))
+	gold.MinIOPS = 2000
+	policy, err := c.EnsureQoSPolicy(ctx, "gold", gold)
+	require.Nil(t, err)
+	require.Equal(t, int64(2000), policy.Qos.MinIOPS)
+	require.Equal(t, []string{"ListQoSPolicies", "ModifyQoSPolicy"}, requestMethods(*requests))
+	require.Equal(t, float64(testQoSPolicyId), (*requests)[1].Params["qosPolicyID"])
+
+	requests, _ = activateRecordingMock(c, listResp,
+		buildSFResponseWrapper(map[string]interface{}{"QoSPolicy": testQoSPolicy("bronze", 100)}))
+	_, err = c.EnsureQoSPolicy(ctx, "bronze", QoS{MinIOPS: 100})
+	require.Nil(t, err)
+	require.Equal(t, []string{"ListQoSPolicies", "CreateQoSPolicy"}, requestMethods(*requests))
+}
+
+func TestDeleteQoSPolicy(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, buildSFResponseWrapper(map[string]interface{}{}))
+	defer mockReset()
+
+	err := c.DeleteQoSPolicy(context.Background(), testQoSPolicyId)
+	require.Nil(t, err)
+	require.Equal(t, float64(testQoSPolicyId), (*requests)[0].Params["qosPolicyID"])
+}
+
+func TestDefaultQoS(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMock(t, c, buildSFResponseWrapper(map[string]interface{}{
+		"minIOPS":   50,
+		"maxIOPS":   15000,
+		"burstIOPS": 15000,
+		"burstTime": 60,
+		"curve":     map[string]float64{"4096": 100},
+	}))
+	defer mockReset()
+
+	ctx := context.Background()
+	qos, err := c.GetDefaultQoS(ctx)
+	require.Nil(t, err)
+	require.Equal(t, QoS{MinIOPS: 50, MaxIOPS: 15000, BurstIOPS: 15000, BurstTime: 60}, qos.QoS())
+
+	requests, _ := activateRecordingMock(c, buildSFResponseWrapper(map[string]interface{}{
+		"minIOPS":   100,
+		"maxIOPS":   20000,
+		"burstIOPS": 20000,
+	}))
+	result, err := c.SetDefaultQoS(ctx, SetDefaultQoSRequest{MinIOPS: 100, MaxIOPS: 20000, BurstIOPS: 20000})
+	require.Nil(t, err)
+	require.Equal(t, int64(100), result.MinIOPS)
+	require.Equal(t, []string{"SetDefaultQoS"}, requestMethods(*requests))
+}
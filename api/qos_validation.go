@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// QoSFieldError describes one invalid field of a request
+type QoSFieldError struct {
+	// JSON path of the field in the request, e.g. qos.minIOPS
+	Field   string `json:"field"`
+	Value   int64  `json:"value"`
+	Message string `json:"message"`
+}
+
+func (e QoSFieldError) String() string {
+	return fmt.Sprintf("%s %d %s", e.Field, e.Value, e.Message)
+}
+
+// QoSValidationError is returned instead of sending a request whose QoS the cluster would reject
+type QoSValidationError struct {
+	Fields []QoSFieldError `json:"fields"`
+}
+
+func (e *QoSValidationError) Error() string {
+	return fmt.Sprintf("%s : %s", e.GetName(), e.GetMessage())
+}
+func (e *QoSValidationError) GetName() string { return ErrInvalidQoS }
+func (e *QoSValidationError) GetMessage() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.String()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// QoSValidator checks QoS settings against the limits of the cluster, which are fetched on first
+// use and cached until Invalidate is called. It is safe for concurrent use.
+type QoSValidator struct {
+	client *Client
+
+	mu     sync.Mutex
+	limits *GetLimitsResult
+	// Incremented by Invalidate so limits fetched before it aren't cached
+	generation int64
+}
+
+func (c *Client) NewQoSValidator() *QoSValidator {
+	return &QoSValidator{client: c}
+}
+
+// Limits returns the cached cluster limits, fetching them if needed. The lock isn't held while
+// fetching, so concurrent first calls may each fetch the limits.
+func (v *QoSValidator) Limits(ctx context.Context) (result *GetLimitsResult, err error) {
+	v.mu.Lock()
+	limits, generation := v.limits, v.generation
+	v.mu.Unlock()
+	if limits != nil {
+		return limits, nil
+	}
+	limits, err = v.client.GetLimits(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting cluster limits for QoS validation")
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.limits == nil && v.generation == generation {
+		v.limits = limits
+	}
+	return limits, nil
+}
+
+// Invalidate drops the cached limits, e.g. after the cluster was upgraded
+func (v *QoSValidator) Invalidate() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.limits = nil
+	v.generation++
+}
+
+// ValidateQoS checks the QoS of a request. Unset (zero) values are left to the cluster, so the
+// relations between values are only checked when both are set.
+func (v *QoSValidator) ValidateQoS(ctx context.Context, qos QoS) (err error) {
+	limits, err := v.Limits(ctx)
+	if err != nil {
+		return err
+	}
+	return validationError(checkQoS(qos, limits))
+}
+
+func (v *QoSValidator) ValidateCreateVolumeRequest(ctx context.Context, req CreateVolumeRequest) (err error) {
+	return v.validateRequest(ctx, req.Qos, req.QosPolicyID)
+}
+
+func (v *QoSValidator) ValidateModifyVolumeRequest(ctx context.Context, req ModifyVolumeRequest) (err error) {
+	return v.validateRequest(ctx, req.Qos, req.QosPolicyID)
+}
+
+func (v *QoSValidator) ValidateModifyVolumesRequest(ctx context.Context, req ModifyVolumesRequest) (err error) {
+	return v.validateRequest(ctx, req.Qos, 0)
+}
+
+func (v *QoSValidator) validateRequest(ctx context.Context, qos QoS, qosPolicyID int64) (err error) {
+	if qos == (QoS{}) {
+		return nil
+	}
+	limits, err := v.Limits(ctx)
+	if err != nil {
+		return err
+	}
+	fields := checkQoS(qos, limits)
+	if qosPolicyID != 0 {
+		fields = append(fields, QoSFieldError{Field: "qosPolicyID", Value: qosPolicyID, Message: "can't be set together with qos"})
+	}
+	return validationError(fields)
+}
+
+func validationError(fields []QoSFieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return &QoSValidationError{Fields: fields}
+}
+
+func checkQoS(qos QoS, limits *GetLimitsResult) (result []QoSFieldError) {
+	checkRange := func(field string, value, min, max int64) {
+		switch {
+		case value == 0:
+		case value < min:
+			result = append(result, QoSFieldError{Field: field, Value: value, Message: fmt.Sprintf("is below the minimum of %d", min)})
+		case value > max:
+			result = append(result, QoSFieldError{Field: field, Value: value, Message: fmt.Sprintf("is above the maximum of %d", max)})
+		}
+	}
+	checkRange("qos.minIOPS", qos.MinIOPS, limits.VolumeMinIOPSMin, limits.VolumeMinIOPSMax)
+	checkRange("qos.maxIOPS", qos.MaxIOPS, limits.VolumeMaxIOPSMin, limits.VolumeMaxIOPSMax)
+	checkRange("qos.burstIOPS", qos.BurstIOPS, limits.VolumeBurstIOPSMin, limits.VolumeBurstIOPSMax)
+	if qos.BurstTime < 0 {
+		result = append(result, QoSFieldError{Field: "qos.burstTime", Value: qos.BurstTime, Message: "can't be negative"})
+	}
+
+	if qos.MinIOPS != 0 && qos.MaxIOPS != 0 && qos.MinIOPS > qos.MaxIOPS {
+		result = append(result, QoSFieldError{Field: "qos.minIOPS", Value: qos.MinIOPS, Message: fmt.Sprintf("is above maxIOPS %d", qos.MaxIOPS)})
+	}
+	if qos.MaxIOPS != 0 && qos.BurstIOPS != 0 && qos.BurstIOPS < qos.MaxIOPS {
+		result = append(result, QoSFieldError{Field: "qos.burstIOPS", Value: qos.BurstIOPS, Message: fmt.Sprintf("is below maxIOPS %d", qos.MaxIOPS)})
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+var testLimits = map[string]interface{}{
+	"volumeMinIOPSMin":   50,
+	"volumeMinIOPSMax":   15000,
+	"volumeMaxIOPSMin":   100,
+	"volumeMaxIOPSMax":   200000,
+	"volumeBurstIOPSMin": 100,
+	"volumeBurstIOPSMax": 200000,
+}
+
+func TestQoSValidator(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, buildSFResponseWrapper(testLimits))
+	defer mockReset()
+
+	v := c.NewQoSValidator()
+	ctx := context.Background()
+	require.Nil(t, v.ValidateQoS(ctx, QoS{MinIOPS: 50, MaxIOPS: 15000, BurstIOPS: 20000, BurstTime: 60}))
+	require.Nil(t, v.ValidateQoS(ctx, QoS{MinIOPS: 1000}))
+
+	err := v.ValidateQoS(ctx, QoS{MinIOPS: 20000, MaxIOPS: 10000, BurstIOPS: 5000})
+	var qosErr *QoSValidationError
+	require.True(t, errors.As(err, &qosErr))
+	require.Equal(t, []QoSFieldError{
+		{Field: "qos.minIOPS", Value: 20000, Message: "is above the maximum of 15000"},
+		{Field: "qos.minIOPS", Value: 20000, Message: "is above maxIOPS 10000"},
+		{Field: "qos.burstIOPS", Value: 5000, Message: "is below maxIOPS 10000"},
+	}, qosErr.Fields)
+	require.Equal(t, ErrInvalidQoS, qosErr.GetName())
+	require.EqualError(t, err, "Invalid QoS : qos.minIOPS 20000 is above the maximum of 15000; "+
+		"qos.minIOPS 20000 is above maxIOPS 10000; qos.burstIOPS 5000 is below maxIOPS 10000")
+
+	err = v.ValidateQoS(ctx, QoS{MaxIOPS: 10, BurstTime: -1})
+	require.True(t, errors.As(err, &qosErr))
+	require.Equal(t, []QoSFieldError{
+		{Field: "qos.maxIOPS", Value: 10, Message: "is below the minimum of 100"},
+		{Field: "qos.burstTime", Value: -1, Message: "can't be negative"},
+	}, qosErr.Fields)
+
+	// The limits are only fetched once until invalidated
+	require.Len(t, *requests, 1)
+	v.Invalidate()
+	require.Nil(t, v.ValidateQoS(ctx, QoS{}))
+	require.Equal(t, []string{"GetLimits", "GetLimits"}, requestMethods(*requests))
+}
+
+func TestQoSValidatorRequests(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, buildSFResponseWrapper(testLimits))
+	defer mockReset()
+
+	v := c.NewQoSValidator()
+	ctx := context.Background()
+	require.Nil(t, v.ValidateCreateVolumeRequest(ctx, CreateVolumeRequest{QosPolicyID: testQoSPolicyId}))
+	require.Len(t, *requests, 0)
+
+	err := v.ValidateCreateVolumeRequest(ctx, CreateVolumeRequest{Qos: QoS{MinIOPS: 100}, QosPolicyID: testQoSPolicyId})
+	require.Equal(t, "qosPolicyID", err.(*QoSValidationError).Fields[0].Field)
+
+	err = v.ValidateModifyVolumeRequest(ctx, ModifyVolumeRequest{VolumeID: testVolumeId, Qos: QoS{BurstIOPS: 300000}})
+	require.Equal(t, "qos.burstIOPS", err.(*QoSValidationError).Fields[0].Field)
+
+	err = v.ValidateModifyVolumesRequest(ctx, ModifyVolumesRequest{VolumeIDs: []int64{testVolumeId}, Qos: QoS{MinIOPS: 10}})
+	require.Equal(t, "qos.minIOPS", err.(*QoSValidationError).Fields[0].Field)
+}
+
+func TestClientValidateQoS(t *testing.T) {
+	c, err := BuildClient(ClientOptions{
+		Target:      "localhost",
+		Username:    "test-username",
+		Password:    "supersecret",
+		ValidateQoS: true,
+	})
+	require.Nil(t, err)
+	requests, mockReset := activateRecordingMock(c,
+		buildSFResponseWrapper(testLimits),
+		buildSFResponseWrapper(map[string]interface{}{"Volume": testVolume}),
+	)
+	defer mockReset()
+
+	ctx := context.Background()
+	_, err = c.CreateVolume(ctx, CreateVolumeRequest{Name: "invalid", AccountID: testAccountId, Qos: QoS{MinIOPS: 10}})
+	var qosErr *QoSValidationError
+	require.True(t, errors.As(err, &qosErr))
+	require.Equal(t, []string{"GetLimits"}, requestMethods(*requests))
+
+	_, err = c.ModifyVolume(ctx, ModifyVolumeRequest{VolumeID: testVolumeId, Qos: QoS{MinIOPS: 100, MaxIOPS: 1000}})
+	require.Nil(t, err)
+	require.Equal(t, []string{"GetLimits", "ModifyVolume"}, requestMethods(*requests))
+
+	_, err = c.ModifyVolumes(ctx, ModifyVolumesRequest{VolumeIDs: []int64{testVolumeId}, Qos: QoS{MaxIOPS: 300000}})
+	require.True(t, errors.As(err, &qosErr))
+	require.Len(t, *requests, 2)
+}
+
+func TestQoSValidatorLimitsError(t *testing.T) {
+	c := getTestClient(t)
+	mockReset := activateMock(t, c, SFResponse{Error: SFAPIError{Code: 500, Name: "xUnknown", Message: "failed"}})
+	defer mockReset()
+
+	err := c.NewQoSValidator().ValidateQoS(context.Background(), QoS{MinIOPS: 100})
+	var sErr *ServiceError
+	require.True(t, errors.As(err, &sErr))
+	require.Contains(t, err.Error(), "getting cluster limits")
+}
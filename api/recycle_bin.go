@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DeletedVolume is a volume in the recycle bin with its parsed deletion times
+type DeletedVolume struct {
+	Volume Volume
+	// When the volume was deleted
+	DeletedAt time.Time
+	// When the cluster purges the volume on its own, zero if unknown
+	PurgeAt time.Time
+}
+
+type RecycleBinOptions struct {
+	// Include deleted virtual volumes
+	IncludeVirtualVolumes bool
+	// Volumes carrying any of these attributes are never purged by Purge, whatever their value
+	ProtectedAttributes []string
+	// Optional rule protecting more volumes from Purge
+	Protect func(v DeletedVolume) bool
+	// Report what Restore and Purge would do without modifying the cluster
+	DryRun bool
+}
+
+type PurgeResult struct {
+	// Volumes purged, or that would be purged with DryRun
+	Purged []DeletedVolume
+	// Volumes old enough to be purged that are protected
+	Protected []DeletedVolume
+	DryRun    bool
+}
+
+// RecycleBin manages the deleted volumes of a cluster, which can be restored until they are purged
+type RecycleBin struct {
+	client *Client
+	opts   RecycleBinOptions
+	now    func() time.Time
+}
+
+func (c *Client) NewRecycleBin(opts RecycleBinOptions) *RecycleBin {
+	return &RecycleBin{
+		client: c,
+		opts:   opts,
+		now:    time.Now,
+	}
+}
+
+// List returns the deleted volumes, oldest deletion first
+func (b *RecycleBin) List(ctx context.Context) (result []DeletedVolume, err error) {
+	req := ListDeletedVolumesRequest{
+		IncludeVirtualVolumes: b.opts.IncludeVirtualVolumes,
+	}
+	volumes, err := b.client.ListDeletedVolumes(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result = make([]DeletedVolume, 0, len(volumes))
+	for _, v := range volumes {
+		dv := DeletedVolume{Volume: v}
+		if dv.DeletedAt, err = parseVolumeTime(v.DeleteTime, "deleteTime", v.VolumeID); err != nil {
+			return nil, err
+		}
+		if dv.PurgeAt, err = parseVolumeTime(v.PurgeTime, "purgeTime", v.VolumeID); err != nil {
+			return nil, err
+		}
+		result = append(result, dv)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DeletedAt.Before(result[j].DeletedAt)
+	})
+	return result, nil
+}
+
+func parseVolumeTime(value string, field string, volID int64) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "parsing %s of volume %d", field, volID)
+	}
+	return t, nil
+}
+
+// Restore restores the deleted volume with the given ID
+func (b *RecycleBin) Restore(ctx context.Context, id int64) (result *DeletedVolume, err error) {
+	return b.restore(ctx, func(v DeletedVolume) bool { return v.Volume.VolumeID == id },
+		fmt.Sprintf("Deleted volume with the given id %d does not exist", id))
+}
+
+// RestoreByName restores the most recently deleted volume with the given name
+func (b *RecycleBin) RestoreByName(ctx context.Context, name string) (result *DeletedVolume, err error) {
+	return b.restore(ctx, func(v DeletedVolume) bool { return v.Volume.Name == name },
+		fmt.Sprintf("Deleted volume with the given name %s does not exist", name))
+}
+
+func (b *RecycleBin) restore(ctx context.Context, match func(v DeletedVolume) bool, notFound string) (result *DeletedVolume, err error) {
+	volumes, err := b.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(volumes) - 1; i >= 0; i-- {
+		if match(volumes[i]) {
+			result = &volumes[i]
+			break
+		}
+	}
+	if result == nil {
+		return nil, BuildRequestError(ErrVolumeIDDoesNotExist, notFound)
+	}
+	if b.opts.DryRun {
+		return result, nil
+	}
+	if err = b.client.RestoreDeletedVolume(ctx, result.Volume.VolumeID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Purge permanently removes the unprotected volumes deleted more than olderThan ago
+func (b *RecycleBin) Purge(ctx context.Context, olderThan time.Duration) (result *PurgeResult, err error) {
+	volumes, err := b.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cutoff := b.now().Add(-olderThan)
+	result = &PurgeResult{DryRun: b.opts.DryRun}
+	ids := []int64{}
+	for _, v := range volumes {
+		// Volumes without a delete time have an unknown age and are left alone
+		if v.DeletedAt.IsZero() || v.DeletedAt.After(cutoff) {
+			continue
+		}
+		if b.isProtected(v) {
+			result.Protected = append(result.Protected, v)
+			continue
+		}
+		result.Purged = append(result.Purged, v)
+		ids = append(ids, v.Volume.VolumeID)
+	}
+	if len(ids) == 0 || b.opts.DryRun {
+		return result, nil
+	}
+	if err = b.client.PurgeDeletedVolumes(ctx, PurgeDeletedVolumesRequest{VolumeIDs: ids}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (b *RecycleBin) isProtected(v DeletedVolume) bool {
+	if attrs, ok := v.Volume.Attributes.(map[string]interface{}); ok {
+		for _, name := range b.opts.ProtectedAttributes {
+			if _, ok := attrs[name]; ok {
+				return true
+			}
+		}
+	}
+	return b.opts.Protect != nil && b.opts.Protect(v)
+}
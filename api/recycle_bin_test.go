@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testRecycleBinNow = time.Date(2021, 6, 2, 12, 0, 0, 0, time.UTC)
+
+func testBinVolume(id int64, name string, deleteTime string, attributes map[string]interface{}) map[string]interface{} {
+	volume := testDeletedVolume(id)
+	volume["name"] = name
+	volume["deleteTime"] = deleteTime
+	volume["purgeTime"] = ""
+	volume["attributes"] = attributes
+	return volume
+}
+
+func getTestRecycleBin(t *testing.T, opts RecycleBinOptions) (*RecycleBin, *[]recordedRequest, func()) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildVolumesResponse(
+			testBinVolume(1, "tenant-a", "2021-06-02T10:00:00Z", map[string]interface{}{}),
+			testBinVolume(2, "tenant-a", "2021-05-30T10:00:00Z", map[string]interface{}{}),
+			testBinVolume(3, "tenant-b", "2021-05-31T10:00:00Z", map[string]interface{}{"legalHold": true}),
+			testBinVolume(4, "tenant-c", "2021-05-29T10:00:00Z", map[string]interface{}{"owner": "finance"}),
+			testBinVolume(5, "tenant-d", "", map[string]interface{}{}),
+		),
+		buildSFResponseWrapper(map[string]interface{}{}),
+	)
+	bin := c.NewRecycleBin(opts)
+	bin.now = func() time.Time { return testRecycleBinNow }
+	return bin, requests, mockReset
+}
+
+func deletedVolumeIDs(volumes []DeletedVolume) []int64 {
+	ids := []int64{}
+	for _, v := range volumes {
+		ids = append(ids, v.Volume.VolumeID)
+	}
+	return ids
+}
+
+func TestRecycleBinList(t *testing.T) {
+	bin, _, mockReset := getTestRecycleBin(t, RecycleBinOptions{})
+	defer mockReset()
+
+	volumes, err := bin.List(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, []int64{5, 4, 2, 3, 1}, deletedVolumeIDs(volumes))
+	require.Equal(t, time.Date(2021, 5, 29, 10, 0, 0, 0, time.UTC), volumes[1].DeletedAt)
+	require.True(t, volumes[0].DeletedAt.IsZero())
+
+	c := getTestClient(t)
+	activateMock(t, c, buildSFResponseWrapper(map[string]interface{}{"Volumes": []map[string]interface{}{testDeletedVolume(testVolumeId)}}))
+	volumes, err = c.NewRecycleBin(RecycleBinOptions{}).List(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2021, 6, 1, 18, 0, 0, 0, time.UTC), volumes[0].PurgeAt)
+
+	activateMock(t, c, buildVolumesResponse(testBinVolume(1, "invalid", "yesterday", nil)))
+	_, err = c.NewRecycleBin(RecycleBinOptions{}).List(context.Background())
+	require.Contains(t, err.Error(), "parsing deleteTime of volume 1")
+}
+
+func TestRecycleBinRestore(t *testing.T) {
+	bin, requests, mockReset := getTestRecycleBin(t, RecycleBinOptions{})
+	defer mockReset()
+
+	ctx := context.Background()
+	restored, err := bin.RestoreByName(ctx, "tenant-a")
+	require.Nil(t, err)
+	require.Equal(t, int64(1), restored.Volume.VolumeID)
+	require.Equal(t, []string{"ListDeletedVolumes", "RestoreDeletedVolume"}, requestMethods(*requests))
+	require.Equal(t, float64(1), (*requests)[1].Params["volumeID"])
+
+	bin, requests, _ = getTestRecycleBin(t, RecycleBinOptions{DryRun: true})
+	restored, err = bin.Restore(ctx, 3)
+	require.Nil(t, err)
+	require.Equal(t, "tenant-b", restored.Volume.Name)
+	require.Equal(t, []string{"ListDeletedVolumes"}, requestMethods(*requests))
+
+	_, err = bin.Restore(ctx, 42)
+	require.Equal(t, ErrVolumeIDDoesNotExist, err.(*RequestError).Name)
+	_, err = bin.RestoreByName(ctx, "tenant-z")
+	require.Contains(t, err.Error(), "tenant-z")
+}
+
+func TestRecycleBinPurge(t *testing.T) {
+	opts := RecycleBinOptions{
+		ProtectedAttributes: []string{"legalHold"},
+		Protect: func(v DeletedVolume) bool {
+			attrs, _ := v.Volume.Attributes.(map[string]interface{})
+			return attrs["owner"] == "finance"
+		},
+	}
+	bin, requests, mockReset := getTestRecycleBin(t, opts)
+	defer mockReset()
+
+	result, err := bin.Purge(context.Background(), 24*time.Hour)
+	require.Nil(t, err)
+	require.False(t, result.DryRun)
+	require.Equal(t, []int64{2}, deletedVolumeIDs(result.Purged))
+	require.Equal(t, []int64{4, 3}, deletedVolumeIDs(result.Protected))
+	require.Equal(t, []string{"ListDeletedVolumes", "PurgeDeletedVolumes"}, requestMethods(*requests))
+	require.Equal(t, []interface{}{float64(2)}, (*requests)[1].Params["volumeIDs"])
+}
+
+func TestRecycleBinPurgeDryRun(t *testing.T) {
+	bin, requests, mockReset := getTestRecycleBin(t, RecycleBinOptions{DryRun: true})
+	defer mockReset()
+
+	ctx := context.Background()
+	result, err := bin.Purge(ctx, time.Hour)
+	require.Nil(t, err)
+	require.True(t, result.DryRun)
+	require.Equal(t, []int64{4, 2, 3, 1}, deletedVolumeIDs(result.Purged))
+	require.Empty(t, result.Protected)
+	require.Equal(t, []string{"ListDeletedVolumes"}, requestMethods(*requests))
+
+	// Nothing old enough, nothing to purge
+	bin, requests, _ = getTestRecycleBin(t, RecycleBinOptions{})
+	result, err = bin.Purge(ctx, 30*24*time.Hour)
+	require.Nil(t, err)
+	require.Empty(t, result.Purged)
+	require.Equal(t, []string{"ListDeletedVolumes"}, requestMethods(*requests))
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// RemoteReplication.State values of a volume pair
+	ReplicationStateActive               = "Active"
+	ReplicationStateIdle                 = "Idle"
+	ReplicationStatePausedDisconnected   = "PausedDisconnected"
+	ReplicationStatePausedManual         = "PausedManual"
+	ReplicationStatePausedManualRemote   = "PausedManualRemote"
+	ReplicationStatePausedMisconfigured  = "PausedMisconfigured"
+	ReplicationStatePausedClusterFull    = "PausedClusterFull"
+	ReplicationStateResumingConnected    = "ResumingConnected"
+	ReplicationStateResumingLocalSync    = "ResumingLocalSync"
+	ReplicationStateResumingDataTransfer = "ResumingDataTransfer"
+	ReplicationStateResumingFullSync     = "ResumingFullSync"
+	ReplicationStateStopped              = "Stopped"
+)
+
+// default replication options
+const (
+	defaultReplicationPollInterval = time.Second * 5
+	defaultRollbackTimeout         = time.Minute
+)
+
+// IsReplicationPaused reports whether state is one of the Paused states, which need an action
+// from the user or the cluster to resume
+func IsReplicationPaused(state string) bool {
+	return strings.HasPrefix(state, "Paused")
+}
+
+type ReplicateVolumeOptions struct {
+	// One of the VolumePairingMode values, Async by default
+	Mode string
+	// Existing volume on the target cluster to replicate to. Its access is set to replicationTarget.
+	// When 0 a volume with the size, 512e setting and QoS of the source is created.
+	TargetVolumeID int64
+	// Account and name of the created target volume. The name defaults to the source volume name.
+	TargetAccountID  int64
+	TargetVolumeName string
+	// Delay between checks of the replication state
+	PollInterval time.Duration
+	// Optional limit on the wait for the replication to become active, in addition to any deadline
+	// on the context
+	Timeout time.Duration
+}
+
+type VolumeReplication struct {
+	SourceVolumeID int64
+	TargetVolumeID int64
+	// Whether the target volume was created by ReplicateVolume
+	TargetCreated bool
+	// Volume pair of the source volume once the replication is active
+	Pair VolumePair
+}
+
+// rollbackSteps undoes the completed steps of a workflow, most recent first
+type rollbackSteps []func(ctx context.Context) error
+
+func (r *rollbackSteps) add(step func(ctx context.Context) error) {
+	*r = append(*r, step)
+}
+
+// run undoes every step and returns err annotated with the steps that couldn't be undone. The
+// rollback gets its own context if ctx is already done.
+func (r rollbackSteps) run(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), defaultRollbackTimeout)
+		defer cancel()
+	}
+	for i := len(r) - 1; i >= 0; i-- {
+		if rollbackErr := r[i](ctx); rollbackErr != nil {
+			err = errors.Wrapf(err, "rollback failed: %v", rollbackErr)
+		}
+	}
+	return err
+}
+
+// ReplicateVolume pairs a volume of the src cluster with a volume of the dst cluster and waits
+// until the replication is active. The clusters must already be paired, see PairClusters. If a step
+// fails the completed ones are undone, a created target volume is deleted but not purged.
+func ReplicateVolume(ctx context.Context, src *Client, dst *Client, srcVolID int64, opts ReplicateVolumeOptions) (result *VolumeReplication, err error) {
+	if opts.Mode == "" {
+		opts.Mode = VolumePairingModeAsync
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultReplicationPollInterval
+	}
+
+	source, err := src.GetVolumeById(ctx, srcVolID)
+	if err != nil {
+		return nil, err
+	}
+	if len(source.VolumePairs) > 0 {
+		return nil, BuildRequestError(ErrVolumeAlreadyPaired, fmt.Sprintf("Volume %d on %s is already paired", srcVolID, src.Target))
+	}
+
+	result = &VolumeReplication{
+		SourceVolumeID: srcVolID,
+	}
+	rollback := rollbackSteps{}
+	target, err := prepareReplicationTarget(ctx, dst, source, opts, &rollback)
+	if err != nil {
+		return nil, rollback.run(ctx, err)
+	}
+	result.TargetVolumeID = target.VolumeID
+	result.TargetCreated = opts.TargetVolumeID == 0
+
+	key, err := src.StartVolumePairing(ctx, srcVolID, opts.Mode)
+	if err != nil {
+		return nil, rollback.run(ctx, errors.Wrapf(err, "starting volume pairing on %s", src.Target))
+	}
+	rollback.add(func(ctx context.Context) error {
+		return src.RemoveVolumePair(ctx, srcVolID)
+	})
+	if err = dst.CompleteVolumePairing(ctx, target.VolumeID, key); err != nil {
+		return nil, rollback.run(ctx, errors.Wrapf(err, "completing volume pairing on %s", dst.Target))
+	}
+	rollback.add(func(ctx context.Context) error {
+		return dst.RemoveVolumePair(ctx, target.VolumeID)
+	})
+
+	pair, err := waitForReplication(ctx, src, srcVolID, opts.PollInterval, opts.Timeout)
+	if err != nil {
+		return nil, rollback.run(ctx, err)
+	}
+	result.Pair = *pair
+	return result, nil
+}
+
+func prepareReplicationTarget(ctx context.Context, dst *Client, source *Volume, opts ReplicateVolumeOptions, rollback *rollbackSteps) (*Volume, error) {
+	if opts.TargetVolumeID == 0 {
+		name := opts.TargetVolumeName
+		if name == "" {
+			name = source.Name
+		}
+		req := CreateVolumeRequest{
+			Name:       name,
+			AccountID:  opts.TargetAccountID,
+			TotalSize:  source.TotalSize,
+			Enable512e: source.Enable512e,
+			Access:     VolumeAccessPolicyReplicationTarget,
+			Qos:        source.Qos.QoS(),
+		}
+		target, err := dst.CreateVolume(ctx, req)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating target volume on %s", dst.Target)
+		}
+		rollback.add(func(ctx context.Context) error {
+			_, err := dst.DeleteVolume(ctx, target.VolumeID)
+			return err
+		})
+		return target, nil
+	}
+
+	target, err := dst.GetVolumeById(ctx, opts.TargetVolumeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(target.VolumePairs) > 0 {
+		return nil, BuildRequestError(ErrVolumeAlreadyPaired, fmt.Sprintf("Volume %d on %s is already paired", target.VolumeID, dst.Target))
+	}
+	if target.TotalSize < source.TotalSize {
+		return nil, BuildRequestError(ErrReplicationTargetTooSmall,
+			fmt.Sprintf("Volume %d on %s has %d bytes, the source needs %d", target.VolumeID, dst.Target, target.TotalSize, source.TotalSize))
+	}
+	if target.Access != VolumeAccessPolicyReplicationTarget {
+		access := target.Access
+		req := ModifyVolumeRequest{
+			VolumeID: target.VolumeID,
+			Access:   VolumeAccessPolicyReplicationTarget,
+		}
+		if _, err = dst.ModifyVolume(ctx, req); err != nil {
+			return nil, errors.Wrapf(err, "setting replication target access on %s", dst.Target)
+		}
+		rollback.add(func(ctx context.Context) error {
+			_, err := dst.ModifyVolume(ctx, ModifyVolumeRequest{VolumeID: target.VolumeID, Access: access})
+			return err
+		})
+	}
+	return target, nil
+}
+
+// waitForReplication polls a paired volume until its pair reports the Active state
+func waitForReplication(ctx context.Context, src *Client, srcVolID int64, pollInterval time.Duration, timeout time.Duration) (*VolumePair, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	state := ""
+	for {
+		volume, err := src.GetVolumeById(ctx, srcVolID)
+		if err != nil && !IsContextError(err) {
+			return nil, err
+		}
+		if err == nil && len(volume.VolumePairs) > 0 {
+			pair := volume.VolumePairs[0]
+			state = pair.RemoteReplication.State
+			if state == ReplicationStateActive {
+				return &pair, nil
+			}
+		}
+
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.Wrapf(ctx.Err(), "waiting for replication of volume %d to become active, last state %q", srcVolID, state)
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Health of a volume pair in a ReplicationHealthReport
+	PairHealthHealthy         = "healthy"
+	PairHealthPaused          = "paused"
+	PairHealthSyncing         = "syncing"
+	PairHealthStalled         = "stalled"
+	PairHealthSnapshotsBehind = "snapshotsBehind"
+	PairHealthRemoteMissing   = "remoteMissing"
+)
+
+const (
+	// Valid SnapshotRemoteStatus.RemoteStatus values
+	SnapshotRemoteStatusPresent    = "Present"
+	SnapshotRemoteStatusNotPresent = "NotPresent"
+	SnapshotRemoteStatusSyncing    = "Syncing"
+	SnapshotRemoteStatusDeleted    = "Deleted"
+	SnapshotRemoteStatusUnknown    = "Unknown"
+)
+
+type ReplicationHealthOptions struct {
+	// Clients of the remote clusters by cluster pair ID, used to flag pairs whose remote volume no
+	// longer exists, deleted volumes that weren't purged yet still exist. Pairs with clusters missing
+	// from the map aren't checked.
+	Remotes map[int64]*Client
+	// Number of volumes requested per ListActivePairedVolumes and remote ListVolumes call, 500 by
+	// default
+	PageSize int64
+	// Number of replicated snapshots that may be missing on the remote cluster before a pair is
+	// reported as behind. Snapshots being synced count as missing.
+	MaxSnapshotsBehind int
+}
+
+type PairHealth struct {
+	VolumeID         int64  `json:"volumeID"`
+	VolumeName       string `json:"volumeName"`
+	ClusterPairID    int64  `json:"clusterPairID"`
+	VolumePairUUID   string `json:"volumePairUUID"`
+	RemoteVolumeID   int64  `json:"remoteVolumeID"`
+	RemoteVolumeName string `json:"remoteVolumeName"`
+	Mode             string `json:"mode"`
+	State            string `json:"state"`
+	StateDetails     string `json:"stateDetails,omitempty"`
+	SnapshotState    string `json:"snapshotState,omitempty"`
+	// Replicated snapshots of the volume that aren't present on the remote cluster yet
+	SnapshotsBehind int `json:"snapshotsBehind"`
+	// Whether the remote volume was checked and not found
+	RemoteMissing bool `json:"remoteMissing"`
+	// One of the PairHealth values
+	Health string `json:"health"`
+}
+
+type ReplicationHealthReport struct {
+	Pairs []PairHealth `json:"pairs"`
+	// Number of pairs per PairHealth value
+	Summary map[string]int `json:"summary"`
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *ReplicationHealthReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteTable writes one aligned line per pair followed by the summary
+func (r *ReplicationHealthReport) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VOLUME\tNAME\tCLUSTER PAIR\tREMOTE VOLUME\tMODE\tSTATE\tSNAPSHOTS BEHIND\tHEALTH")
+	for _, p := range r.Pairs {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%s\t%s\t%d\t%s\n",
+			p.VolumeID, p.VolumeName, p.ClusterPairID, p.RemoteVolumeID, p.Mode, p.State, p.SnapshotsBehind, p.Health)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d pairs: %d %s, %d %s, %d %s, %d %s, %d %s, %d %s\n", len(r.Pairs),
+		r.Summary[PairHealthHealthy], PairHealthHealthy,
+		r.Summary[PairHealthSyncing], PairHealthSyncing,
+		r.Summary[PairHealthPaused], PairHealthPaused,
+		r.Summary[PairHealthStalled], PairHealthStalled,
+		r.Summary[PairHealthSnapshotsBehind], PairHealthSnapshotsBehind,
+		r.Summary[PairHealthRemoteMissing], PairHealthRemoteMissing)
+	return err
+}
+
+// GetReplicationHealthReport classifies the pairs of every actively paired volume of the cluster.
+// A pair is remoteMissing if its remote volume no longer exists on the remote cluster, paused if
+// its replication or snapshot replication is paused, syncing while its replication is resuming,
+// e.g. during the initial sync, stalled if its replication is otherwise inactive and snapshotsBehind
+// if too many of its replicated snapshots are missing on the remote cluster.
+func (c *Client) GetReplicationHealthReport(ctx context.Context, opts ReplicationHealthOptions) (result *ReplicationHealthReport, err error) {
+	snapshots, err := c.ListSnapshots(ctx, ListSnapshotsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	snapshotsByVolume := map[int64][]Snapshot{}
+	for _, s := range snapshots {
+		if s.EnableRemoteReplication {
+			snapshotsByVolume[s.VolumeID] = append(snapshotsByVolume[s.VolumeID], s)
+		}
+	}
+
+	result = &ReplicationHealthReport{
+		Pairs:   []PairHealth{},
+		Summary: map[string]int{},
+	}
+	// Remote volume IDs to look up, by cluster pair ID
+	remoteIDs := map[int64][]int64{}
+	it := c.IterateActivePairedVolumes(ctx, ListActivePairedVolumesRequest{Limit: opts.PageSize})
+	for it.Next() {
+		v := it.Volume()
+		for _, pair := range v.VolumePairs {
+			h := PairHealth{
+				VolumeID:         v.VolumeID,
+				VolumeName:       v.Name,
+				ClusterPairID:    pair.ClusterPairID,
+				VolumePairUUID:   pair.VolumePairUUID,
+				RemoteVolumeID:   pair.RemoteVolumeID,
+				RemoteVolumeName: pair.RemoteVolumeName,
+				Mode:             pair.RemoteReplication.Mode,
+				State:            pair.RemoteReplication.State,
+				StateDetails:     pair.RemoteReplication.StateDetails,
+				SnapshotState:    pair.RemoteReplication.SnapshotReplication.State,
+			}
+			for _, s := range snapshotsByVolume[v.VolumeID] {
+				if !snapshotPresentOnRemote(s, pair.VolumePairUUID) {
+					h.SnapshotsBehind++
+				}
+			}
+			result.Pairs = append(result.Pairs, h)
+			remoteIDs[pair.ClusterPairID] = append(remoteIDs[pair.ClusterPairID], pair.RemoteVolumeID)
+		}
+	}
+	if err = it.Err(); err != nil {
+		return nil, err
+	}
+
+	existing := map[int64]map[int64]bool{}
+	for clusterPairID, remote := range opts.Remotes {
+		if len(remoteIDs[clusterPairID]) == 0 {
+			continue
+		}
+		if existing[clusterPairID], err = existingVolumeIDs(ctx, remote, remoteIDs[clusterPairID], opts.PageSize); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range result.Pairs {
+		h := &result.Pairs[i]
+		if ids, ok := existing[h.ClusterPairID]; ok && !ids[h.RemoteVolumeID] {
+			h.RemoteMissing = true
+		}
+		h.Health = pairHealth(*h, opts.MaxSnapshotsBehind)
+		result.Summary[h.Health]++
+	}
+	return result, nil
+}
+
+// existingVolumeIDs returns the given volume IDs that exist on the cluster, including deleted
+// volumes that weren't purged yet
+func existingVolumeIDs(ctx context.Context, c *Client, ids []int64, pageSize int64) (result map[int64]bool, err error) {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	result = map[int64]bool{}
+	for start := 0; start < len(ids); start += int(pageSize) {
+		end := start + int(pageSize)
+		if end > len(ids) {
+			end = len(ids)
+		}
+		volumes, err := c.ListVolumes(ctx, ListVolumesRequest{VolumeIDs: ids[start:end]})
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing remote volumes on %s", c.Target)
+		}
+		for _, v := range volumes {
+			result[v.VolumeID] = true
+		}
+	}
+	deleted, err := c.ListDeletedVolumes(ctx, ListDeletedVolumesRequest{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing deleted remote volumes on %s", c.Target)
+	}
+	for _, v := range deleted {
+		result[v.VolumeID] = true
+	}
+	return result, nil
+}
+
+func snapshotPresentOnRemote(s Snapshot, volumePairUUID string) bool {
+	for _, rs := range s.RemoteStatuses {
+		if rs.VolumePairUUID == volumePairUUID {
+			return rs.RemoteStatus == SnapshotRemoteStatusPresent
+		}
+	}
+	return false
+}
+
+func pairHealth(h PairHealth, maxSnapshotsBehind int) string {
+	switch {
+	case h.RemoteMissing:
+		return PairHealthRemoteMissing
+	case IsReplicationPaused(h.State) || IsReplicationPaused(h.SnapshotState):
+		return PairHealthPaused
+	case isReplicationSyncing(h.State):
+		return PairHealthSyncing
+	case h.State != ReplicationStateActive:
+		return PairHealthStalled
+	case h.SnapshotsBehind > maxSnapshotsBehind:
+		return PairHealthSnapshotsBehind
+	}
+	return PairHealthHealthy
+}
+
+// isReplicationSyncing reports whether the pair is (re)establishing replication, e.g. during the
+// initial sync of a new pair
+func isReplicationSyncing(state string) bool {
+	switch state {
+	case ReplicationStateResumingConnected, ReplicationStateResumingLocalSync,
+		ReplicationStateResumingDataTransfer, ReplicationStateResumingFullSync:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testPairedVolume returns a volume paired with remote volume id+100
+func testPairedVolume(id int64, state string) map[string]interface{} {
+	pairs := testReplicationPair(state)
+	pairs[0]["remoteVolumeID"] = id + 100
+	pairs[0]["volumePairUUID"] = fmt.Sprintf("pair-%d", id)
+	return testReplicationVolume(map[string]interface{}{
+		"volumeID":    id,
+		"name":        fmt.Sprintf("volume-%d", id),
+		"volumePairs": pairs,
+	})
+}
+
+func testReplicatedSnapshot(volumeID int64, status string) map[string]interface{} {
+	return map[string]interface{}{
+		"snapshotID":              volumeID * 10,
+		"volumeID":                volumeID,
+		"enableRemoteReplication": true,
+		"remoteStatuses": []map[string]interface{}{
+			{"remoteStatus": status, "volumePairUUID": fmt.Sprintf("pair-%d", volumeID)},
+		},
+	}
+}
+
+func TestGetReplicationHealthReport(t *testing.T) {
+	c := getTestClient(t)
+	remote := getTestRemoteClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildSFResponseWrapper(map[string]interface{}{"Snapshots": []map[string]interface{}{
+			testReplicatedSnapshot(1, SnapshotRemoteStatusPresent),
+			testReplicatedSnapshot(1, SnapshotRemoteStatusSyncing),
+			testReplicatedSnapshot(3, SnapshotRemoteStatusNotPresent),
+			testReplicatedSnapshot(3, SnapshotRemoteStatusSyncing),
+			{"snapshotID": 99, "volumeID": 3, "enableRemoteReplication": false},
+		}}),
+		buildVolumesResponse(testPairedVolume(1, ReplicationStateActive), testPairedVolume(2, ReplicationStatePausedManual)),
+		buildVolumesResponse(testPairedVolume(3, ReplicationStateActive), testPairedVolume(4, ReplicationStateActive)),
+		buildVolumesResponse(testPairedVolume(5, ReplicationStateResumingFullSync)),
+	)
+	defer mockReset()
+	// Volume 104 doesn't exist anymore and volume 105 was deleted but not purged
+	remoteRequests, _ := activateRecordingMock(remote,
+		buildVolumesResponse(
+			testReplicationVolume(map[string]interface{}{"volumeID": 101}),
+			testReplicationVolume(map[string]interface{}{"volumeID": 102}),
+		),
+		buildVolumesResponse(testReplicationVolume(map[string]interface{}{"volumeID": 103})),
+		buildVolumesResponse(),
+		buildVolumesResponse(testReplicationVolume(map[string]interface{}{"volumeID": 105, "status": "deleted"})),
+	)
+
+	opts := ReplicationHealthOptions{
+		Remotes:            map[int64]*Client{testClusterPairId: remote},
+		PageSize:           2,
+		MaxSnapshotsBehind: 1,
+	}
+	ctx := context.Background()
+	report, err := c.GetReplicationHealthReport(ctx, opts)
+	require.Nil(t, err)
+	require.Equal(t, []string{"ListSnapshots", "ListActivePairedVolumes", "ListActivePairedVolumes", "ListActivePairedVolumes"}, requestMethods(*requests))
+	require.Equal(t, float64(3), (*requests)[2].Params["startVolumeID"])
+	require.Equal(t, float64(5), (*requests)[3].Params["startVolumeID"])
+	require.Equal(t, []string{"ListVolumes", "ListVolumes", "ListVolumes", "ListDeletedVolumes"}, requestMethods(*remoteRequests))
+	require.Equal(t, []interface{}{float64(101), float64(102)}, (*remoteRequests)[0].Params["volumeIDs"])
+	require.Equal(t, []interface{}{float64(105)}, (*remoteRequests)[2].Params["volumeIDs"])
+
+	health := []string{}
+	for _, p := range report.Pairs {
+		health = append(health, p.Health)
+	}
+	require.Equal(t, []string{PairHealthHealthy, PairHealthPaused, PairHealthSnapshotsBehind, PairHealthRemoteMissing, PairHealthSyncing}, health)
+	require.Equal(t, 1, report.Pairs[0].SnapshotsBehind)
+	require.Equal(t, 2, report.Pairs[2].SnapshotsBehind)
+	require.True(t, report.Pairs[3].RemoteMissing)
+	require.Equal(t, map[string]int{
+		PairHealthHealthy:         1,
+		PairHealthPaused:          1,
+		PairHealthSnapshotsBehind: 1,
+		PairHealthRemoteMissing:   1,
+		PairHealthSyncing:         1,
+	}, report.Summary)
+}
+
+func TestReplicationHealthReportOutput(t *testing.T) {
+	report := ReplicationHealthReport{
+		Pairs: []PairHealth{{
+			VolumeID:       1,
+			VolumeName:     "volume-1",
+			ClusterPairID:  testClusterPairId,
+			RemoteVolumeID: 101,
+			Mode:           VolumePairingModeAsync,
+			State:          ReplicationStatePausedManual,
+			Health:         PairHealthPaused,
+		}},
+		Summary: map[string]int{PairHealthPaused: 1},
+	}
+
+	out := bytes.Buffer{}
+	require.Nil(t, report.WriteJSON(&out))
+	decoded := ReplicationHealthReport{}
+	require.Nil(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Equal(t, report, decoded)
+
+	out.Reset()
+	require.Nil(t, report.WriteTable(&out))
+	require.Equal(t, `VOLUME  NAME      CLUSTER PAIR  REMOTE VOLUME  MODE   STATE         SNAPSHOTS BEHIND  HEALTH
+1       volume-1  1             101            Async  PausedManual  0                 paused
+
+1 pairs: 0 healthy, 0 syncing, 1 paused, 0 stalled, 0 snapshotsBehind, 0 remoteMissing
+`, out.String())
+}
+
+func TestPairHealth(t *testing.T) {
+	tests := []struct {
+		state  string
+		health string
+	}{
+		{ReplicationStateActive, PairHealthHealthy},
+		{ReplicationStateResumingFullSync, PairHealthSyncing},
+		{ReplicationStateResumingDataTransfer, PairHealthSyncing},
+		{ReplicationStateResumingLocalSync, PairHealthSyncing},
+		{ReplicationStatePausedDisconnected, PairHealthPaused},
+		{ReplicationStateStopped, PairHealthStalled},
+		{ReplicationStateIdle, PairHealthStalled},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.health, pairHealth(PairHealth{State: tt.state}, 0), tt.state)
+	}
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const testTargetVolumeId int64 = 3776
+const testVolumePairingKey = "7b22766f6c756d654944223a333537367d"
+
+// testReplicationVolume returns testVolume with the given fields replaced
+func testReplicationVolume(fields map[string]interface{}) map[string]interface{} {
+	v := map[string]interface{}{}
+	for k, val := range testVolume {
+		v[k] = val
+	}
+	for k, val := range fields {
+		v[k] = val
+	}
+	return v
+}
+
+func testReplicationPair(state string) []map[string]interface{} {
+	return []map[string]interface{}{{
+		"clusterPairID":    testClusterPairId,
+		"remoteVolumeID":   testTargetVolumeId,
+		"remoteVolumeName": "solidfire-sdk-test",
+		"volumePairUUID":   "951d5294-bb2c-48d9-9a9e-99cd34fafd2b",
+		"remoteReplication": map[string]interface{}{
+			"mode":  VolumePairingModeAsync,
+			"state": state,
+		},
+	}}
+}
+
+func buildVolumesResponse(volumes ...map[string]interface{}) SFResponse {
+	return buildSFResponseWrapper(map[string]interface{}{"Volumes": volumes})
+}
+
+var testReplicationOptions = ReplicateVolumeOptions{
+	TargetAccountID: testAccountId,
+	PollInterval:    time.Millisecond,
+}
+
+func requestMethods(requests []recordedRequest) []string {
+	methods := []string{}
+	for _, r := range requests {
+		methods = append(methods, r.Method)
+	}
+	return methods
+}
+
+func TestIsReplicationPaused(t *testing.T) {
+	require.True(t, IsReplicationPaused(ReplicationStatePausedDisconnected))
+	require.True(t, IsReplicationPaused(ReplicationStatePausedMisconfigured))
+	require.False(t, IsReplicationPaused(ReplicationStateActive))
+	require.False(t, IsReplicationPaused(ReplicationStateResumingFullSync))
+}
+
+func TestReplicateVolume(t *testing.T) {
+	src := getTestClient(t)
+	dst := getTestRemoteClient(t)
+	srcRequests, mockReset := activateRecordingMock(src,
+		buildVolumesResponse(testReplicationVolume(nil)),
+		buildSFResponseWrapper(map[string]interface{}{"VolumePairingKey": testVolumePairingKey}),
+		buildVolumesResponse(testReplicationVolume(map[string]interface{}{"volumePairs": testReplicationPair(ReplicationStateResumingConnected)})),
+		buildVolumesResponse(testReplicationVolume(map[string]interface{}{"volumePairs": testReplicationPair(ReplicationStateActive)})),
+	)
+	defer mockReset()
+	dstRequests, _ := activateRecordingMock(dst,
+		buildSFResponseWrapper(map[string]interface{}{"Volume": testReplicationVolume(map[string]interface{}{
+			"volumeID": testTargetVolumeId,
+			"access":   VolumeAccessPolicyReplicationTarget,
+		})}),
+		buildSFResponseWrapper(map[string]interface{}{}),
+	)
+
+	ctx := context.Background()
+	resp, err := ReplicateVolume(ctx, src, dst, testVolumeId, testReplicationOptions)
+	require.Nil(t, err)
+	require.Equal(t, testTargetVolumeId, resp.TargetVolumeID)
+	require.True(t, resp.TargetCreated)
+	require.Equal(t, ReplicationStateActive, resp.Pair.RemoteReplication.State)
+
+	require.Equal(t, []string{"ListVolumes", "StartVolumePairing", "ListVolumes", "ListVolumes"}, requestMethods(*srcRequests))
+	require.Equal(t, VolumePairingModeAsync, (*srcRequests)[1].Params["mode"])
+	require.Equal(t, []string{"CreateVolume", "CompleteVolumePairing"}, requestMethods(*dstRequests))
+	create := (*dstRequests)[0].Params
+	require.Equal(t, "solidfire-sdk-test", create["name"])
+	require.Equal(t, VolumeAccessPolicyReplicationTarget, create["access"])
+	require.Equal(t, float64(1.5*Gigabytes), create["totalSize"])
+	require.Equal(t, float64(testTargetVolumeId), (*dstRequests)[1].Params["volumeID"])
+	require.Equal(t, testVolumePairingKey, (*dstRequests)[1].Params["volumePairingKey"])
+}
+
+func TestReplicateVolumeExistingTarget(t *testing.T) {
+	src := getTestClient(t)
+	dst := getTestRemoteClient(t)
+	_, mockReset := activateRecordingMock(src,
+		buildVolumesResponse(testReplicationVolume(nil)),
+		buildSFResponseWrapper(map[string]interface{}{"VolumePairingKey": testVolumePairingKey}),
+		buildVolumesResponse(testReplicationVolume(map[string]interface{}{"volumePairs": testReplicationPair(ReplicationStateActive)})),
+	)
+	defer mockReset()
+	dstRequests, _ := activateRecordingMock(dst,
+		buildVolumesResponse(testReplicationVolume(map[string]interface{}{"volumeID": testTargetVolumeId})),
+		buildSFResponseWrapper(map[string]interface{}{"Volume": testReplicationVolume(map[string]interface{}{"volumeID": testTargetVolumeId})}),
+		buildSFResponseWrapper(map[string]interface{}{}),
+	)
+
+	opts := testReplicationOptions
+	opts.TargetVolumeID = testTargetVolumeId
+	opts.Mode = VolumePairingModeSync
+	ctx := context.Background()
+	resp, err := ReplicateVolume(ctx, src, dst, testVolumeId, opts)
+	require.Nil(t, err)
+	require.False(t, resp.TargetCreated)
+	require.Equal(t, []string{"ListVolumes", "ModifyVolume", "CompleteVolumePairing"}, requestMethods(*dstRequests))
+	require.Equal(t, VolumeAccessPolicyReplicationTarget, (*dstRequests)[1].Params["access"])
+}
+
+func TestReplicateVolumeTargetTooSmall(t *testing.T) {
+	src := getTestClient(t)
+	dst := getTestRemoteClient(t)
+	mockReset := activateMock(t, src, buildVolumesResponse(testReplicationVolume(nil)))
+	defer mockReset()
+	dstRequests, _ := activateRecordingMock(dst, buildVolumesResponse(testReplicationVolume(map[string]interface{}{
+		"volumeID":  testTargetVolumeId,
+		"totalSize": int64(Gigabytes),
+	})))
+
+	opts := testReplicationOptions
+	opts.TargetVolumeID = testTargetVolumeId
+	_, err := ReplicateVolume(context.Background(), src, dst, testVolumeId, opts)
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrReplicationTargetTooSmall, reqErr.Name)
+	require.Len(t, *dstRequests, 1)
+}
+
+func TestReplicateVolumeAlreadyPaired(t *testing.T) {
+	src := getTestClient(t)
+	dst := getTestRemoteClient(t)
+	mockReset := activateMock(t, src, buildVolumesResponse(testReplicationVolume(map[string]interface{}{
+		"volumePairs": testReplicationPair(ReplicationStateActive),
+	})))
+	defer mockReset()
+
+	_, err := ReplicateVolume(context.Background(), src, dst, testVolumeId, testReplicationOptions)
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrVolumeAlreadyPaired, reqErr.Name)
+}
+
+func TestReplicateVolumeRollback(t *testing.T) {
+	src := getTestClient(t)
+	dst := getTestRemoteClient(t)
+	srcRequests, mockReset := activateRecordingMock(src,
+		buildVolumesResponse(testReplicationVolume(nil)),
+		buildSFResponseWrapper(map[string]interface{}{"VolumePairingKey": testVolumePairingKey}),
+		buildSFResponseWrapper(map[string]interface{}{}),
+	)
+	defer mockReset()
+	dstRequests, _ := activateRecordingMock(dst,
+		buildSFResponseWrapper(map[string]interface{}{"Volume": testReplicationVolume(map[string]interface{}{"volumeID": testTargetVolumeId})}),
+		SFResponse{
+			Error: SFAPIError{
+				Code:    500,
+				Name:    ErrInvalidParameter,
+				Message: "Invalid volume pairing key",
+			},
+		},
+		buildSFResponseWrapper(map[string]interface{}{"Volume": testReplicationVolume(map[string]interface{}{"volumeID": testTargetVolumeId})}),
+	)
+
+	_, err := ReplicateVolume(context.Background(), src, dst, testVolumeId, testReplicationOptions)
+	require.NotNil(t, err)
+	require.NotContains(t, err.Error(), "rollback failed")
+	require.Equal(t, []string{"ListVolumes", "StartVolumePairing", "RemoveVolumePair"}, requestMethods(*srcRequests))
+	require.Equal(t, []string{"CreateVolume", "CompleteVolumePairing", "DeleteVolume"}, requestMethods(*dstRequests))
+	require.Equal(t, float64(testTargetVolumeId), (*dstRequests)[2].Params["volumeID"])
+}
+
+func TestReplicateVolumeTimeout(t *testing.T) {
+	src := getTestClient(t)
+	dst := getTestRemoteClient(t)
+	srcRequests, mockReset := activateRecordingMock(src,
+		buildVolumesResponse(testReplicationVolume(nil)),
+		buildSFResponseWrapper(map[string]interface{}{"VolumePairingKey": testVolumePairingKey}),
+		buildVolumesResponse(testReplicationVolume(map[string]interface{}{"volumePairs": testReplicationPair(ReplicationStatePausedMisconfigured)})),
+	)
+	defer mockReset()
+	dstRequests, _ := activateRecordingMock(dst,
+		buildVolumesResponse(testReplicationVolume(map[string]interface{}{
+			"volumeID": testTargetVolumeId,
+			"access":   VolumeAccessPolicyReplicationTarget,
+		})),
+		buildSFResponseWrapper(map[string]interface{}{}),
+	)
+
+	opts := testReplicationOptions
+	opts.TargetVolumeID = testTargetVolumeId
+	opts.Timeout = time.Millisecond * 20
+	_, err := ReplicateVolume(context.Background(), src, dst, testVolumeId, opts)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Contains(t, err.Error(), ReplicationStatePausedMisconfigured)
+	srcMethods := requestMethods(*srcRequests)
+	require.Equal(t, "RemoveVolumePair", srcMethods[len(srcMethods)-1])
+	require.Equal(t, []string{"ListVolumes", "CompleteVolumePairing", "RemoveVolumePair"}, requestMethods(*dstRequests))
+}
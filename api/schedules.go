@@ -0,0 +1,333 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	// Valid Frequency types
+	FrequencyTimeInterval = "Time Interval"
+	FrequencyDaysOfWeek   = "Days Of Week"
+	FrequencyDaysOfMonth  = "Days Of Month"
+
+	// The only schedule type supported by Element
+	ScheduleTypeSnapshot = "Snapshot"
+)
+
+func NewTimeIntervalFrequency(days int64, hours int64, minutes int64) Frequency {
+	return Frequency{
+		Type:    FrequencyTimeInterval,
+		Days:    days,
+		Hours:   hours,
+		Minutes: minutes,
+	}
+}
+
+// NewDaysOfWeekFrequency runs every week on the given days at hours:minutes
+func NewDaysOfWeekFrequency(hours int64, minutes int64, days ...int64) Frequency {
+	weekdays := []Weekday{}
+	for _, d := range days {
+		weekdays = append(weekdays, Weekday{Day: d, Offset: 1})
+	}
+	return Frequency{
+		Type:     FrequencyDaysOfWeek,
+		Hours:    hours,
+		Minutes:  minutes,
+		Weekdays: weekdays,
+	}
+}
+
+// NewDaysOfMonthFrequency runs every month on the given days (1-31) at hours:minutes
+func NewDaysOfMonthFrequency(hours int64, minutes int64, monthdays ...int64) Frequency {
+	return Frequency{
+		Type:      FrequencyDaysOfMonth,
+		Hours:     hours,
+		Minutes:   minutes,
+		Monthdays: monthdays,
+	}
+}
+
+func invalidSchedule(format string, a ...interface{}) error {
+	return BuildRequestError(ErrInvalidSchedule, fmt.Sprintf(format, a...))
+}
+
+func (f Frequency) Validate() error {
+	if f.Minutes < 0 || f.Minutes > 59 {
+		return invalidSchedule("minutes must be between 0 and 59, got %d", f.Minutes)
+	}
+	if f.Hours < 0 || f.Hours > 23 {
+		return invalidSchedule("hours must be between 0 and 23, got %d", f.Hours)
+	}
+	switch f.Type {
+	case FrequencyTimeInterval:
+		if f.Days < 0 {
+			return invalidSchedule("days must not be negative, got %d", f.Days)
+		}
+		if f.Days == 0 && f.Hours == 0 && f.Minutes == 0 {
+			return invalidSchedule("time interval must be greater than zero")
+		}
+		if len(f.Weekdays) > 0 || len(f.Monthdays) > 0 {
+			return invalidSchedule("weekdays and monthdays can't be set on a %s frequency", f.Type)
+		}
+	case FrequencyDaysOfWeek:
+		if len(f.Weekdays) == 0 {
+			return invalidSchedule("at least one weekday is required")
+		}
+		for _, w := range f.Weekdays {
+			if w.Day < 0 || w.Day > 6 {
+				return invalidSchedule("weekday must be between 0 (Sunday) and 6 (Saturday), got %d", w.Day)
+			}
+			if w.Offset < 1 {
+				return invalidSchedule("weekday offset must be at least 1, got %d", w.Offset)
+			}
+		}
+		if f.Days != 0 || len(f.Monthdays) > 0 {
+			return invalidSchedule("days and monthdays can't be set on a %s frequency", f.Type)
+		}
+	case FrequencyDaysOfMonth:
+		if len(f.Monthdays) == 0 {
+			return invalidSchedule("at least one monthday is required")
+		}
+		for _, d := range f.Monthdays {
+			if d < 1 || d > 31 {
+				return invalidSchedule("monthday must be between 1 and 31, got %d", d)
+			}
+		}
+		if f.Days != 0 || len(f.Weekdays) > 0 {
+			return invalidSchedule("days and weekdays can't be set on a %s frequency", f.Type)
+		}
+	default:
+		return invalidSchedule("unknown frequency type %q", f.Type)
+	}
+	return nil
+}
+
+func (s Schedule) Validate() error {
+	if s.Name == "" {
+		return invalidSchedule("name is required")
+	}
+	if len(s.ScheduleInfo.VolumeIDs) == 0 {
+		return invalidSchedule("at least one volume is required")
+	}
+	return s.Frequency.Validate()
+}
+
+// scheduleParams is the flat representation of a schedule used by the Element API
+type scheduleParams struct {
+	ScheduleID         int64                  `json:"scheduleID,omitempty"`
+	ScheduleName       string                 `json:"scheduleName"`
+	ScheduleType       string                 `json:"scheduleType"`
+	Attributes         map[string]interface{} `json:"attributes"`
+	Hours              int64                  `json:"hours"`
+	Minutes            int64                  `json:"minutes"`
+	Weekdays           []Weekday              `json:"weekdays,omitempty"`
+	Monthdays          []int64                `json:"monthdays,omitempty"`
+	Paused             bool                   `json:"paused"`
+	Recurring          bool                   `json:"recurring"`
+	RunNextInterval    bool                   `json:"runNextInterval"`
+	StartingDate       string                 `json:"startingDate,omitempty"`
+	ToBeDeleted        bool                   `json:"toBeDeleted,omitempty"`
+	ScheduleInfo       map[string]interface{} `json:"scheduleInfo"`
+	HasError           bool                   `json:"-"`
+	LastRunStatus      string                 `json:"-"`
+	LastRunTimeStarted string                 `json:"-"`
+}
+
+func buildScheduleParams(s Schedule) scheduleParams {
+	f := s.Frequency
+	hours := f.Hours
+	if f.Type == FrequencyTimeInterval {
+		hours += f.Days * 24
+	}
+	info := map[string]interface{}{}
+	if s.ScheduleInfo.SnapshotName != "" {
+		info["name"] = s.ScheduleInfo.SnapshotName
+	}
+	if s.ScheduleInfo.Retention != "" {
+		info["retention"] = s.ScheduleInfo.Retention
+	}
+	if s.ScheduleInfo.EnableRemoteReplication {
+		info["enableRemoteReplication"] = true
+	}
+	if len(s.ScheduleInfo.VolumeIDs) == 1 {
+		info["volumeID"] = s.ScheduleInfo.VolumeIDs[0]
+	} else {
+		info["volumes"] = s.ScheduleInfo.VolumeIDs
+	}
+	// Keep the attributes set by the cluster or other tools, only the frequency is ours
+	attributes := map[string]interface{}{}
+	for k, v := range s.Attributes {
+		attributes[k] = v
+	}
+	attributes["frequency"] = f.Type
+	return scheduleParams{
+		ScheduleID:      s.ScheduleID,
+		ScheduleName:    s.Name,
+		ScheduleType:    ScheduleTypeSnapshot,
+		Attributes:      attributes,
+		Hours:           hours,
+		Minutes:         f.Minutes,
+		Weekdays:        f.Weekdays,
+		Monthdays:       f.Monthdays,
+		Paused:          s.Paused,
+		Recurring:       s.Recurring,
+		RunNextInterval: s.RunNextInterval,
+		StartingDate:    s.StartingDate,
+		ToBeDeleted:     s.ToBeDeleted,
+		ScheduleInfo:    info,
+	}
+}
+
+// scheduleInt64 converts a number returned by the cluster, which uses strings for some ids
+func scheduleInt64(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case float64:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	}
+	return 0, fmt.Errorf("unexpected volume id %v", v)
+}
+
+func (p scheduleParams) schedule() (result Schedule, err error) {
+	frequencyType, _ := p.Attributes["frequency"].(string)
+	f := Frequency{
+		Type:    frequencyType,
+		Hours:   p.Hours,
+		Minutes: p.Minutes,
+	}
+	// The cluster returns empty lists for the fields the frequency type doesn't use
+	switch f.Type {
+	case FrequencyTimeInterval:
+		f.Days = p.Hours / 24
+		f.Hours = p.Hours % 24
+	case FrequencyDaysOfWeek:
+		f.Weekdays = p.Weekdays
+	case FrequencyDaysOfMonth:
+		f.Monthdays = p.Monthdays
+	}
+
+	info := ScheduleInfo{}
+	info.SnapshotName, _ = p.ScheduleInfo["name"].(string)
+	info.Retention, _ = p.ScheduleInfo["retention"].(string)
+	info.EnableRemoteReplication, _ = p.ScheduleInfo["enableRemoteReplication"].(bool)
+	if v, ok := p.ScheduleInfo["volumeID"]; ok {
+		id, err := scheduleInt64(v)
+		if err != nil {
+			return result, err
+		}
+		info.VolumeIDs = append(info.VolumeIDs, id)
+	}
+	if volumes, ok := p.ScheduleInfo["volumes"].([]interface{}); ok {
+		for _, v := range volumes {
+			id, err := scheduleInt64(v)
+			if err != nil {
+				return result, err
+			}
+			info.VolumeIDs = append(info.VolumeIDs, id)
+		}
+	}
+
+	result = Schedule{
+		ScheduleID:         p.ScheduleID,
+		Name:               p.ScheduleName,
+		Frequency:          f,
+		ScheduleInfo:       info,
+		Paused:             p.Paused,
+		Recurring:          p.Recurring,
+		RunNextInterval:    p.RunNextInterval,
+		StartingDate:       p.StartingDate,
+		ToBeDeleted:        p.ToBeDeleted,
+		HasError:           p.HasError,
+		LastRunStatus:      p.LastRunStatus,
+		LastRunTimeStarted: p.LastRunTimeStarted,
+		Attributes:         p.Attributes,
+	}
+	return result, nil
+}
+
+func decodeSchedule(v interface{}) (result Schedule, err error) {
+	p := scheduleParams{}
+	if err = mapstructure.Decode(v, &p); err != nil {
+		return result, err
+	}
+	return p.schedule()
+}
+
+// CreateSchedule validates and creates a snapshot schedule. The returned schedule is the given one
+// with its new ScheduleID.
+func (c *Client) CreateSchedule(ctx context.Context, req CreateScheduleRequest) (result *Schedule, err error) {
+	if err = req.Schedule.Validate(); err != nil {
+		return nil, err
+	}
+	csr := CreateScheduleResult{}
+	err = c.request(ctx, "CreateSchedule", buildScheduleParams(req.Schedule), &csr)
+	if err != nil {
+		return nil, err
+	}
+	schedule := req.Schedule
+	schedule.ScheduleID = csr.ScheduleID
+	return &schedule, nil
+}
+
+// ModifySchedule replaces the settings of the schedule with the given ScheduleID. All fields are
+// sent, so start from the schedule returned by GetSchedule.
+func (c *Client) ModifySchedule(ctx context.Context, req ModifyScheduleRequest) (result *Schedule, err error) {
+	if req.Schedule.ScheduleID == 0 {
+		return nil, invalidSchedule("scheduleID is required to modify a schedule")
+	}
+	if err = req.Schedule.Validate(); err != nil {
+		return nil, err
+	}
+	msr := struct{ Schedule map[string]interface{} }{}
+	err = c.request(ctx, "ModifySchedule", buildScheduleParams(req.Schedule), &msr)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := decodeSchedule(msr.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (c *Client) ListSchedules(ctx context.Context) (result []Schedule, err error) {
+	lsr := struct{ Schedules []map[string]interface{} }{}
+	err = c.request(ctx, "ListSchedules", struct{}{}, &lsr)
+	if err != nil {
+		return nil, err
+	}
+	result = []Schedule{}
+	for _, s := range lsr.Schedules {
+		schedule, err := decodeSchedule(s)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, schedule)
+	}
+	return result, nil
+}
+
+func (c *Client) GetSchedule(ctx context.Context, id int64) (result *Schedule, err error) {
+	req := GetScheduleRequest{
+		ScheduleID: id,
+	}
+	gsr := struct{ Schedule map[string]interface{} }{}
+	err = c.request(ctx, "GetSchedule", req, &gsr)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := decodeSchedule(gsr.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
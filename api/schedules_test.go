@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+const testScheduleId int64 = 4
+const testScheduleName = "solidfire-sdk-schedule-test"
+
+var testSchedule = map[string]interface{}{
+	"attributes":         map[string]interface{}{"frequency": FrequencyDaysOfWeek, "lastModifiedBy": "admin"},
+	"hasError":           false,
+	"hours":              2,
+	"minutes":            30,
+	"lastRunStatus":      "Success",
+	"lastRunTimeStarted": "2021-05-24T02:30:00Z",
+	"monthdays":          []int64{},
+	"paused":             false,
+	"recurring":          true,
+	"runNextInterval":    false,
+	"scheduleID":         testScheduleId,
+	"scheduleInfo": map[string]interface{}{
+		"name":      "nightly",
+		"retention": "72:00:00",
+		"volumeID":  "3576",
+	},
+	"scheduleName": testScheduleName,
+	"scheduleType": ScheduleTypeSnapshot,
+	"startingDate": "2021-05-01T00:00:00Z",
+	"toBeDeleted":  false,
+	"weekdays": []map[string]interface{}{
+		{"day": 1, "offset": 1},
+		{"day": 3, "offset": 1},
+	},
+}
+
+func TestFrequencyValidate(t *testing.T) {
+	valid := []Frequency{
+		NewTimeIntervalFrequency(1, 12, 0),
+		NewTimeIntervalFrequency(0, 0, 15),
+		NewDaysOfWeekFrequency(2, 30, 1, 3, 5),
+		NewDaysOfMonthFrequency(23, 59, 1, 15, 31),
+	}
+	for _, f := range valid {
+		require.Nil(t, f.Validate(), "%+v", f)
+	}
+
+	invalid := []Frequency{
+		{},
+		{Type: "Hourly", Hours: 1},
+		NewTimeIntervalFrequency(0, 0, 0),
+		NewTimeIntervalFrequency(0, 24, 0),
+		NewTimeIntervalFrequency(0, 1, 60),
+		NewDaysOfWeekFrequency(2, 30),
+		NewDaysOfWeekFrequency(2, 30, 7),
+		{Type: FrequencyDaysOfWeek, Weekdays: []Weekday{{Day: 1, Offset: 0}}},
+		NewDaysOfMonthFrequency(2, 30),
+		NewDaysOfMonthFrequency(2, 30, 0),
+		NewDaysOfMonthFrequency(2, 30, 32),
+		{Type: FrequencyDaysOfMonth, Monthdays: []int64{1}, Weekdays: []Weekday{{Day: 1, Offset: 1}}},
+	}
+	for _, f := range invalid {
+		err := f.Validate()
+		var reqErr *RequestError
+		require.True(t, errors.As(err, &reqErr), "%+v", f)
+		require.Equal(t, ErrInvalidSchedule, reqErr.Name)
+	}
+}
+
+func TestCreateSchedule(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"ScheduleID": testScheduleId})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := CreateScheduleRequest{
+		Schedule: Schedule{
+			Name:      testScheduleName,
+			Frequency: NewTimeIntervalFrequency(1, 6, 0),
+			Recurring: true,
+			ScheduleInfo: ScheduleInfo{
+				SnapshotName: "every-30h",
+				VolumeIDs:    []int64{testVolumeId, testSnapshotVolumeId},
+			},
+		},
+	}
+	resp, err := c.CreateSchedule(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, testScheduleId, resp.ScheduleID)
+	require.Equal(t, testScheduleName, resp.Name)
+
+	params := (*requests)[0].Params
+	require.Equal(t, "CreateSchedule", (*requests)[0].Method)
+	require.Equal(t, testScheduleName, params["scheduleName"])
+	require.Equal(t, ScheduleTypeSnapshot, params["scheduleType"])
+	require.Equal(t, map[string]interface{}{"frequency": FrequencyTimeInterval}, params["attributes"])
+	require.Equal(t, float64(30), params["hours"])
+	require.Equal(t, float64(0), params["minutes"])
+	require.Equal(t, true, params["recurring"])
+	require.Equal(t, map[string]interface{}{
+		"name":    "every-30h",
+		"volumes": []interface{}{float64(testVolumeId), float64(testSnapshotVolumeId)},
+	}, params["scheduleInfo"])
+}
+
+func TestCreateScheduleInvalid(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"ScheduleID": testScheduleId})
+	requests, mockReset := activateRecordingMock(c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	req := CreateScheduleRequest{
+		Schedule: Schedule{
+			Name:         testScheduleName,
+			Frequency:    NewDaysOfWeekFrequency(2, 30),
+			ScheduleInfo: ScheduleInfo{VolumeIDs: []int64{testVolumeId}},
+		},
+	}
+	_, err := c.CreateSchedule(ctx, req)
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrInvalidSchedule, reqErr.Name)
+
+	req.Schedule.Frequency = NewDaysOfWeekFrequency(2, 30, 1)
+	req.Schedule.ScheduleInfo.VolumeIDs = nil
+	_, err = c.CreateSchedule(ctx, req)
+	require.True(t, errors.As(err, &reqErr))
+	require.Len(t, *requests, 0)
+}
+
+func TestGetSchedule(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"Schedule": testSchedule})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.GetSchedule(ctx, testScheduleId)
+	require.Nil(t, err)
+	require.Equal(t, testScheduleId, resp.ScheduleID)
+	require.Equal(t, testScheduleName, resp.Name)
+	require.Equal(t, NewDaysOfWeekFrequency(2, 30, 1, 3), resp.Frequency)
+	require.Equal(t, []int64{testVolumeId}, resp.ScheduleInfo.VolumeIDs)
+	require.Equal(t, "nightly", resp.ScheduleInfo.SnapshotName)
+	require.Equal(t, "72:00:00", resp.ScheduleInfo.Retention)
+	require.Equal(t, "Success", resp.LastRunStatus)
+	require.True(t, resp.Recurring)
+}
+
+func TestListSchedules(t *testing.T) {
+	c := getTestClient(t)
+	interval := make(map[string]interface{})
+	for k, v := range testSchedule {
+		interval[k] = v
+	}
+	interval["scheduleID"] = testScheduleId + 1
+	interval["attributes"] = map[string]interface{}{"frequency": FrequencyTimeInterval}
+	interval["hours"] = 50
+	interval["minutes"] = 0
+	interval["weekdays"] = []map[string]interface{}{}
+	interval["scheduleInfo"] = map[string]interface{}{"volumes": []int64{testVolumeId, testSnapshotVolumeId}}
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"Schedules": []map[string]interface{}{testSchedule, interval}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.ListSchedules(ctx)
+	require.Nil(t, err)
+	require.Len(t, resp, 2)
+	require.Equal(t, FrequencyDaysOfWeek, resp[0].Frequency.Type)
+	require.Equal(t, FrequencyTimeInterval, resp[1].Frequency.Type)
+	require.Equal(t, int64(2), resp[1].Frequency.Days)
+	require.Equal(t, int64(2), resp[1].Frequency.Hours)
+	require.Equal(t, []int64{testVolumeId, testSnapshotVolumeId}, resp[1].ScheduleInfo.VolumeIDs)
+}
+
+func TestModifySchedule(t *testing.T) {
+	c := getTestClient(t)
+	paused := make(map[string]interface{})
+	for k, v := range testSchedule {
+		paused[k] = v
+	}
+	paused["paused"] = true
+	requests, mockReset := activateRecordingMock(c,
+		buildSFResponseWrapper(map[string]interface{}{"Schedule": testSchedule}),
+		buildSFResponseWrapper(map[string]interface{}{"Schedule": paused}),
+	)
+	defer mockReset()
+
+	ctx := context.Background()
+	schedule, err := c.GetSchedule(ctx, testScheduleId)
+	require.Nil(t, err)
+	schedule.Paused = true
+	resp, err := c.ModifySchedule(ctx, ModifyScheduleRequest{Schedule: *schedule})
+	require.Nil(t, err)
+	require.True(t, resp.Paused)
+
+	params := (*requests)[1].Params
+	require.Equal(t, "ModifySchedule", (*requests)[1].Method)
+	require.Equal(t, float64(testScheduleId), params["scheduleID"])
+	require.Equal(t, true, params["paused"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"day": float64(1), "offset": float64(1)},
+		map[string]interface{}{"day": float64(3), "offset": float64(1)},
+	}, params["weekdays"])
+	require.Equal(t, float64(testVolumeId), params["scheduleInfo"].(map[string]interface{})["volumeID"])
+	// The other attributes are kept, the frequency follows the schedule
+	schedule.Frequency = NewTimeIntervalFrequency(0, 12, 0)
+	_, err = c.ModifySchedule(ctx, ModifyScheduleRequest{Schedule: *schedule})
+	require.Nil(t, err)
+	require.Equal(t, map[string]interface{}{"frequency": FrequencyTimeInterval, "lastModifiedBy": "admin"}, (*requests)[2].Params["attributes"])
+	require.Equal(t, FrequencyDaysOfWeek, schedule.Attributes["frequency"])
+
+	// A schedule without an ID can't be modified
+	schedule.ScheduleID = 0
+	_, err = c.ModifySchedule(ctx, ModifyScheduleRequest{Schedule: *schedule})
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrInvalidSchedule, reqErr.Name)
+	require.Len(t, *requests, 3)
+}
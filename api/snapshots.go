@@ -52,3 +52,64 @@ func (c *Client) GetSnapshotsByVolumeId(ctx context.Context, id int64) (result [
 	}
 	return c.ListSnapshots(ctx, req)
 }
+
+// RollbackToSnapshot replaces the contents of a volume with a snapshot. When SaveCurrentState is
+// set the result holds the snapshot of the pre-rollback state.
+func (c *Client) RollbackToSnapshot(ctx context.Context, req RollbackToSnapshotRequest) (result *RollbackToSnapshotResult, err error) {
+	rsr := RollbackToSnapshotResult{}
+	err = c.request(ctx, "RollbackToSnapshot", req, &rsr)
+	if err != nil {
+		return nil, err
+	}
+	result = &rsr
+	return result, err
+}
+
+type SnapshotRollback struct {
+	VolumeID int64
+	// Snapshot the volume was rolled back to
+	SnapshotID int64
+	// Snapshot holding the pre-rollback state, 0 unless SaveCurrentState was requested
+	SavedSnapshotID int64
+}
+
+// SafeRollbackToSnapshot rolls a volume back after checking that the snapshot belongs to the
+// volume and that no bulk volume job is reading from or writing to the volume. When
+// SaveCurrentState is set without a Name the saved snapshot is named after the rollback target.
+func (c *Client) SafeRollbackToSnapshot(ctx context.Context, req RollbackToSnapshotRequest) (result *SnapshotRollback, err error) {
+	snapshot, err := c.GetSnapshotById(ctx, req.SnapshotID)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.VolumeID != req.VolumeID {
+		return nil, BuildRequestError(ErrSnapshotVolumeMismatch,
+			fmt.Sprintf("Snapshot %d belongs to volume %d, not volume %d", req.SnapshotID, snapshot.VolumeID, req.VolumeID))
+	}
+
+	jobs, err := c.ListBulkVolumeJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range jobs {
+		if j.SrcVolumeID == req.VolumeID && j.Status != BulkVolumeJobStatusDone && j.Status != BulkVolumeJobStatusFailed {
+			return nil, BuildRequestError(ErrActiveBulkVolumeJobs,
+				fmt.Sprintf("Bulk volume job %d is %s on volume %d", j.BulkVolumeID, j.Status, req.VolumeID))
+		}
+	}
+
+	if req.SaveCurrentState && req.Name == "" {
+		req.Name = fmt.Sprintf("pre-rollback-to-%d", req.SnapshotID)
+	}
+	rsr, err := c.RollbackToSnapshot(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result = &SnapshotRollback{
+		VolumeID:   req.VolumeID,
+		SnapshotID: req.SnapshotID,
+	}
+	if req.SaveCurrentState {
+		result.SavedSnapshotID = rsr.SnapshotID
+	}
+	return result, nil
+}
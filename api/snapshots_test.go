@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -114,3 +115,85 @@ func TestGetSnapshotByVolumeId(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, testSnapshotVolumeId, resp[0].VolumeID)
 }
+
+func TestRollbackToSnapshot(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"SnapshotID": testSnapshotId + 1, "Checksum": "0x0"})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+	ctx := context.Background()
+	req := RollbackToSnapshotRequest{
+		VolumeID:         testSnapshotVolumeId,
+		SnapshotID:       testSnapshotId,
+		SaveCurrentState: true,
+	}
+	resp, err := c.RollbackToSnapshot(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, testSnapshotId+1, resp.SnapshotID)
+}
+
+func TestSafeRollbackToSnapshot(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildSFResponseWrapper(map[string]interface{}{"Snapshots": []map[string]interface{}{testSnapshot}}),
+		buildSFResponseWrapper(map[string]interface{}{"BulkVolumeJobs": []map[string]interface{}{
+			{"bulkVolumeID": 1, "srcVolumeID": testSnapshotVolumeId, "status": BulkVolumeJobStatusDone},
+			{"bulkVolumeID": 2, "srcVolumeID": testVolumeId, "status": BulkVolumeJobStatusActive},
+		}}),
+		buildSFResponseWrapper(map[string]interface{}{"SnapshotID": testSnapshotId + 1, "Checksum": "0x0"}),
+	)
+	defer mockReset()
+	ctx := context.Background()
+	req := RollbackToSnapshotRequest{
+		VolumeID:         testSnapshotVolumeId,
+		SnapshotID:       testSnapshotId,
+		SaveCurrentState: true,
+	}
+	resp, err := c.SafeRollbackToSnapshot(ctx, req)
+	require.Nil(t, err)
+	require.Equal(t, testSnapshotVolumeId, resp.VolumeID)
+	require.Equal(t, testSnapshotId, resp.SnapshotID)
+	require.Equal(t, testSnapshotId+1, resp.SavedSnapshotID)
+	require.Len(t, *requests, 3)
+	require.Equal(t, "RollbackToSnapshot", (*requests)[2].Method)
+	require.Equal(t, "pre-rollback-to-9500", (*requests)[2].Params["name"])
+}
+
+func TestSafeRollbackToSnapshotWrongVolume(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildSFResponseWrapper(map[string]interface{}{"Snapshots": []map[string]interface{}{testSnapshot}}),
+	)
+	defer mockReset()
+	ctx := context.Background()
+	req := RollbackToSnapshotRequest{
+		VolumeID:   testVolumeId,
+		SnapshotID: testSnapshotId,
+	}
+	_, err := c.SafeRollbackToSnapshot(ctx, req)
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrSnapshotVolumeMismatch, reqErr.Name)
+	require.Len(t, *requests, 1)
+}
+
+func TestSafeRollbackToSnapshotActiveBulkVolumeJob(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c,
+		buildSFResponseWrapper(map[string]interface{}{"Snapshots": []map[string]interface{}{testSnapshot}}),
+		buildSFResponseWrapper(map[string]interface{}{"BulkVolumeJobs": []map[string]interface{}{
+			{"bulkVolumeID": 1, "srcVolumeID": testSnapshotVolumeId, "status": BulkVolumeJobStatusActive},
+		}}),
+	)
+	defer mockReset()
+	ctx := context.Background()
+	req := RollbackToSnapshotRequest{
+		VolumeID:   testSnapshotVolumeId,
+		SnapshotID: testSnapshotId,
+	}
+	_, err := c.SafeRollbackToSnapshot(ctx, req)
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	require.Equal(t, ErrActiveBulkVolumeJobs, reqErr.Name)
+	require.Len(t, *requests, 2)
+}
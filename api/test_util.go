@@ -1,7 +1,9 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
+	"sync"
 	"testing"
 
 	"github.com/jarcoal/httpmock"
@@ -9,8 +11,17 @@ import (
 )
 
 func getTestClient(t *testing.T) (client *Client) {
+	return getTestClientForTarget(t, "localhost")
+}
+
+// getTestRemoteClient returns a client for a second cluster, mocks of the two clients don't interfere
+func getTestRemoteClient(t *testing.T) (client *Client) {
+	return getTestClientForTarget(t, "remotehost")
+}
+
+func getTestClientForTarget(t *testing.T, target string) (client *Client) {
 	opts := ClientOptions{
-		Target:   "localhost",
+		Target:   target,
 		Username: "test-username",
 		Password: "supersecret",
 		Version:  "12.3",
@@ -30,10 +41,63 @@ func activateMock(t *testing.T, c *Client, respBody interface{}) (mockReset func
 	if err != nil {
 		require.Fail(t, "Failed to Mock response with ", respBody, err)
 	}
+	// Echo the request id back like the cluster does so the client's id check passes
+	if sfr, ok := respBody.(SFResponse); ok {
+		responder = func(req *http.Request) (*http.Response, error) {
+			body := struct {
+				Id int64 `json:"id"`
+			}{}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			resp := sfr
+			resp.Id = body.Id
+			return httpmock.NewJsonResponse(http.StatusOK, resp)
+		}
+	}
 	httpmock.RegisterResponder("POST", c.ApiUrl, responder)
 	return httpmock.DeactivateAndReset
 }
 
+// activateMockSequence replies with the given responses in order, repeating the last one once
+// the sequence is exhausted
+func activateMockSequence(c *Client, responses ...SFResponse) (mockReset func()) {
+	_, mockReset = activateRecordingMock(c, responses...)
+	return mockReset
+}
+
+// recordedRequest is a JSON-RPC request body captured by activateRecordingMock
+type recordedRequest struct {
+	Id     int64                  `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// activateRecordingMock behaves like activateMockSequence and also records every request body
+func activateRecordingMock(c *Client, responses ...SFResponse) (requests *[]recordedRequest, mockReset func()) {
+	httpmock.ActivateNonDefault(c.HTTPClient.GetClient())
+
+	var mu sync.Mutex
+	recorded := []recordedRequest{}
+	httpmock.RegisterResponder("POST", c.ApiUrl, func(req *http.Request) (*http.Response, error) {
+		body := recordedRequest{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		mu.Lock()
+		i := len(recorded)
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		recorded = append(recorded, body)
+		mu.Unlock()
+		resp := responses[i]
+		resp.Id = body.Id
+		return httpmock.NewJsonResponse(http.StatusOK, resp)
+	})
+	return &recorded, httpmock.DeactivateAndReset
+}
+
 func activateMockHttpErr(c *Client, status int) (mockReset func()) {
 	httpmock.ActivateNonDefault(c.HTTPClient.GetClient())
 
@@ -44,8 +108,15 @@ func activateMockHttpErr(c *Client, status int) (mockReset func()) {
 
 func buildSFResponseWrapper(resultValue map[string]interface{}) (response SFResponse) {
 	response = SFResponse{
-		Id:     1,
+		Id:     0,
 		Result: resultValue,
 	}
 	return response
 }
+
+// unregisterAsyncResultType removes a type registered by a test so the test can run again
+func unregisterAsyncResultType(resultType string) {
+	asyncResultTypesMu.Lock()
+	defer asyncResultTypesMu.Unlock()
+	delete(asyncResultTypes, resultType)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TLS option errors
+const (
+	ErrInvalidCACert           = "No valid certificates found in CA bundle"
+	ErrInvalidFingerprint      = "Server certificate fingerprint must be a hex encoded SHA-256 digest"
+	ErrIncompleteClientCert    = "Client certificate and key must both be provided"
+	ErrFingerprintMismatch     = "Server certificate does not match the pinned fingerprint"
+	ErrCertificateVerifyFailed = "TLS certificate verification failed"
+)
+
+// CertificateVerificationError is returned when the connection to the cluster was rejected
+// because its certificate could not be verified. Err holds the underlying x509 or pinning error.
+type CertificateVerificationError struct {
+	Target string
+	Err    error
+}
+
+func (e *CertificateVerificationError) Error() string {
+	return fmt.Sprintf("%s for %s: %s", ErrCertificateVerifyFailed, e.Target, e.Err)
+}
+func (e *CertificateVerificationError) Unwrap() error { return e.Err }
+
+type fingerprintMismatchError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *fingerprintMismatchError) Error() string {
+	return fmt.Sprintf("%s: expected %s, got %s", ErrFingerprintMismatch, e.Expected, e.Actual)
+}
+
+func isCertificateError(err error) bool {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var systemRoots x509.SystemRootsError
+	var mismatch *fingerprintMismatchError
+	return errors.As(err, &unknownAuthority) || errors.As(err, &hostname) || errors.As(err, &invalid) ||
+		errors.As(err, &systemRoots) || errors.As(err, &mismatch)
+}
+
+func parseFingerprint(fingerprint string) ([]byte, error) {
+	cleaned := strings.NewReplacer(":", "", " ", "").Replace(fingerprint)
+	digest, err := hex.DecodeString(cleaned)
+	if err != nil || len(digest) != sha256.Size {
+		return nil, errors.New(ErrInvalidFingerprint)
+	}
+	return digest, nil
+}
+
+func buildTLSConfig(opts ClientOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	}
+
+	caPEM := opts.CACertPEM
+	if opts.CACertFile != "" {
+		data, err := ioutil.ReadFile(opts.CACertFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading CA bundle")
+		}
+		caPEM = append(append([]byte{}, caPEM...), data...)
+	}
+	if len(caPEM) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, errors.New(ErrInvalidCACert)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.ServerCertFingerprint != "" {
+		expected, err := parseFingerprint(opts.ServerCertFingerprint)
+		if err != nil {
+			return nil, err
+		}
+		// A pinned certificate is usually self-signed, so unless a CA bundle was also given the
+		// pin replaces chain verification rather than adding to it
+		if len(caPEM) == 0 {
+			cfg.InsecureSkipVerify = true
+		}
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return &fingerprintMismatchError{Expected: hex.EncodeToString(expected)}
+			}
+			actual := sha256.Sum256(cs.PeerCertificates[0].Raw)
+			if !bytes.Equal(actual[:], expected) {
+				return &fingerprintMismatchError{
+					Expected: hex.EncodeToString(expected),
+					Actual:   hex.EncodeToString(actual[:]),
+				}
+			}
+			return nil
+		}
+	}
+
+	certPEM, keyPEM := opts.ClientCertPEM, opts.ClientKeyPEM
+	if opts.ClientCertFile != "" || opts.ClientKeyFile != "" {
+		if opts.ClientCertFile == "" || opts.ClientKeyFile == "" {
+			return nil, errors.New(ErrIncompleteClientCert)
+		}
+		var err error
+		if certPEM, err = ioutil.ReadFile(opts.ClientCertFile); err != nil {
+			return nil, errors.Wrap(err, "reading client certificate")
+		}
+		if keyPEM, err = ioutil.ReadFile(opts.ClientKeyFile); err != nil {
+			return nil, errors.Wrap(err, "reading client key")
+		}
+	}
+	if len(certPEM) > 0 || len(keyPEM) > 0 {
+		if len(certPEM) == 0 || len(keyPEM) == 0 {
+			return nil, errors.New(ErrIncompleteClientCert)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
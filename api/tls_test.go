@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonRPCHandler answers every request with an empty result, echoing the request id
+var jsonRPCHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	body := struct {
+		Id int64 `json:"id"`
+	}{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(SFResponse{Id: body.Id, Result: map[string]interface{}{}})
+})
+
+func tlsTestOptions(t *testing.T, s *httptest.Server) ClientOptions {
+	u, err := url.Parse(s.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+	return ClientOptions{
+		Target:   u.Hostname(),
+		Port:     port,
+		Version:  "12.3",
+		Username: "test-username",
+		Password: "supersecret",
+	}
+}
+
+func serverCertPEM(s *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+}
+
+func callTLSTestServer(t *testing.T, opts ClientOptions) error {
+	c, err := BuildClient(opts)
+	require.NoError(t, err)
+	result := map[string]interface{}{}
+	return c.request(context.Background(), "GetClusterInfo", nil, &result)
+}
+
+// generateClientCert returns a self-signed ECDSA certificate usable for client authentication
+func generateClientCert(t *testing.T) (certPEM []byte, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "solidfire-sdk-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+func TestTLSDefaultRejectsUntrustedCert(t *testing.T) {
+	s := httptest.NewTLSServer(jsonRPCHandler)
+	defer s.Close()
+
+	opts := tlsTestOptions(t, s)
+	opts.UseRetry = true
+	err := callTLSTestServer(t, opts)
+	require.Error(t, err)
+	var certErr *CertificateVerificationError
+	require.True(t, errors.As(err, &certErr), "expected CertificateVerificationError, got %v", err)
+	require.Equal(t, opts.Target, certErr.Target)
+	var unknownAuthority x509.UnknownAuthorityError
+	require.True(t, errors.As(err, &unknownAuthority))
+}
+
+func TestTLSCACertPEM(t *testing.T) {
+	s := httptest.NewTLSServer(jsonRPCHandler)
+	defer s.Close()
+
+	opts := tlsTestOptions(t, s)
+	opts.CACertPEM = serverCertPEM(s)
+	require.NoError(t, callTLSTestServer(t, opts))
+}
+
+func TestTLSCACertFile(t *testing.T) {
+	s := httptest.NewTLSServer(jsonRPCHandler)
+	defer s.Close()
+
+	dir, err := ioutil.TempDir("", "solidfire-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, ioutil.WriteFile(caFile, serverCertPEM(s), 0600))
+
+	opts := tlsTestOptions(t, s)
+	opts.CACertFile = caFile
+	require.NoError(t, callTLSTestServer(t, opts))
+
+	// A file without certificates is rejected when the client is built
+	badFile := filepath.Join(dir, "bad.pem")
+	require.NoError(t, ioutil.WriteFile(badFile, []byte("not a certificate"), 0600))
+	opts.CACertFile = badFile
+	_, err = BuildClient(opts)
+	require.EqualError(t, err, ErrInvalidCACert)
+
+	opts.CACertFile = filepath.Join(dir, "missing.pem")
+	_, err = BuildClient(opts)
+	require.Error(t, err)
+}
+
+func TestTLSFingerprint(t *testing.T) {
+	s := httptest.NewTLSServer(jsonRPCHandler)
+	defer s.Close()
+
+	sum := sha256.Sum256(s.Certificate().Raw)
+	opts := tlsTestOptions(t, s)
+
+	// Colon separated upper case, as printed by openssl
+	parts := []string{}
+	for _, b := range sum {
+		parts = append(parts, strings.ToUpper(hex.EncodeToString([]byte{b})))
+	}
+	opts.ServerCertFingerprint = strings.Join(parts, ":")
+	require.NoError(t, callTLSTestServer(t, opts))
+
+	wrong := sha256.Sum256([]byte("some other certificate"))
+	opts.ServerCertFingerprint = hex.EncodeToString(wrong[:])
+	err := callTLSTestServer(t, opts)
+	var certErr *CertificateVerificationError
+	require.True(t, errors.As(err, &certErr), "expected CertificateVerificationError, got %v", err)
+	require.Contains(t, err.Error(), ErrFingerprintMismatch)
+
+	opts.ServerCertFingerprint = "abc"
+	_, err = BuildClient(opts)
+	require.EqualError(t, err, ErrInvalidFingerprint)
+}
+
+func TestTLSInsecureSkipVerify(t *testing.T) {
+	s := httptest.NewTLSServer(jsonRPCHandler)
+	defer s.Close()
+
+	opts := tlsTestOptions(t, s)
+	opts.InsecureSkipVerify = true
+	require.NoError(t, callTLSTestServer(t, opts))
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	certPEM, keyPEM := generateClientCert(t)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(certPEM))
+
+	s := httptest.NewUnstartedServer(jsonRPCHandler)
+	s.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	s.StartTLS()
+	defer s.Close()
+
+	opts := tlsTestOptions(t, s)
+	opts.CACertPEM = serverCertPEM(s)
+	require.Error(t, callTLSTestServer(t, opts))
+
+	opts.ClientCertPEM = certPEM
+	opts.ClientKeyPEM = keyPEM
+	require.NoError(t, callTLSTestServer(t, opts))
+
+	opts.ClientKeyPEM = nil
+	_, err := BuildClient(opts)
+	require.EqualError(t, err, ErrIncompleteClientCert)
+}
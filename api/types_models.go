@@ -16,7 +16,22 @@ const (
 	Gigabytes = 1000 * 1000 * 1000
 )
 
+// Frequency describes when a schedule runs. For FrequencyTimeInterval Days, Hours and Minutes are
+// the time between runs. For FrequencyDaysOfWeek and FrequencyDaysOfMonth Hours and Minutes are the
+// time of day of each run.
 type Frequency struct {
+	Type      string    `json:"type"`
+	Days      int64     `json:"days,omitempty"`
+	Hours     int64     `json:"hours"`
+	Minutes   int64     `json:"minutes"`
+	Weekdays  []Weekday `json:"weekdays,omitempty"`
+	Monthdays []int64   `json:"monthdays,omitempty"`
+}
+
+// Weekday is a day of a FrequencyDaysOfWeek schedule. Day is 0 for Sunday through 6 for Saturday.
+type Weekday struct {
+	Day    int64 `json:"day"`
+	Offset int64 `json:"offset"`
 }
 
 type CHAPSecret struct {
@@ -36,16 +51,16 @@ type Account struct {
 }
 
 type AddedNode struct {
-	NodeID          int64    `json:"nodeID,omitempty"`
-	PendingNodeID   int64    `json:"pendingNodeID"`
-	ActiveNodeKey   string   `json:"activeNodeKey,omitempty"`
-	AssignedNodeID  int64    `json:"assignedNodeID,omitempty"`
-	AsyncHandle     int64    `json:"asyncHandle,omitempty"`
-	Cip             string   `json:"cip,omitempty"`
-	Mip             string   `json:"mip,omitempty"`
-	PlatformInfo    Platform `json:"platformInfo,omitempty"`
-	Sip             string   `json:"sip,omitempty"`
-	SoftwareVersion string   `json:"softwareVersion,omitempty"`
+	NodeID          int64         `json:"nodeID,omitempty"`
+	PendingNodeID   int64         `json:"pendingNodeID"`
+	ActiveNodeKey   string        `json:"activeNodeKey,omitempty"`
+	AssignedNodeID  int64         `json:"assignedNodeID,omitempty"`
+	AsyncHandle     AsyncResultID `json:"asyncHandle,omitempty"`
+	Cip             string        `json:"cip,omitempty"`
+	Mip             string        `json:"mip,omitempty"`
+	PlatformInfo    Platform      `json:"platformInfo,omitempty"`
+	Sip             string        `json:"sip,omitempty"`
+	SoftwareVersion string        `json:"softwareVersion,omitempty"`
 }
 
 type AddressBlock struct {
@@ -71,6 +86,25 @@ type BackupTarget struct {
 	Attributes     interface{} `json:"attributes,omitempty"`
 }
 
+type BulkVolumeAsyncResult struct {
+	BvID            int64       `json:"bvID"`
+	VolumeID        int64       `json:"volumeID,omitempty"`
+	Key             string      `json:"key,omitempty"`
+	Url             string      `json:"url,omitempty"`
+	Message         string      `json:"message,omitempty"`
+	PercentComplete int64       `json:"percentComplete,omitempty"`
+	Status          string      `json:"status,omitempty"`
+	Attributes      interface{} `json:"attributes,omitempty"`
+}
+
+const (
+	// BulkVolumeJob status values
+	BulkVolumeJobStatusPreparing = "preparing"
+	BulkVolumeJobStatusActive    = "active"
+	BulkVolumeJobStatusDone      = "done"
+	BulkVolumeJobStatusFailed    = "failed"
+)
+
 type BulkVolumeJob struct {
 	BulkVolumeID    int64       `json:"bulkVolumeID"`
 	CreateTime      string      `json:"createTime"`
@@ -87,6 +121,14 @@ type BulkVolumeJob struct {
 	Attributes      interface{} `json:"attributes"`
 }
 
+type CloneAsyncResult struct {
+	CloneID         int64  `json:"cloneID"`
+	VolumeID        int64  `json:"volumeID"`
+	GroupCloneID    int64  `json:"groupCloneID,omitempty"`
+	Message         string `json:"message,omitempty"`
+	PercentComplete int64  `json:"percentComplete,omitempty"`
+}
+
 type CloneMultipleVolumeParams struct {
 	VolumeID     int64       `json:"volumeID"`
 	Access       string      `json:"access,omitempty"`
@@ -254,6 +296,11 @@ type Drive struct {
 	Attributes                interface{} `json:"attributes"`
 }
 
+type DriveAddAsyncResult struct {
+	DriveIDs []int64 `json:"driveIDs,omitempty"`
+	Message  string  `json:"message,omitempty"`
+}
+
 type DriveConfigInfo struct {
 	CanonicalName        string `json:"canonicalName"`
 	Connected            bool   `json:"connected"`
@@ -341,6 +388,11 @@ type DriveInfo struct {
 	Attributes interface{} `json:"attributes"`
 }
 
+type DriveRemovalAsyncResult struct {
+	DriveIDs []int64 `json:"driveIDs,omitempty"`
+	Message  string  `json:"message,omitempty"`
+}
+
 type DriveStats struct {
 	ActiveSessions         int64  `json:"activeSessions,omitempty"`
 	DriveID                int64  `json:"driveID,omitempty"`
@@ -737,15 +789,15 @@ type PairedCluster struct {
 }
 
 type PendingActiveNode struct {
-	ActiveNodeKey   string   `json:"activeNodeKey"`
-	AssignedNodeID  int64    `json:"assignedNodeID"`
-	AsyncHandle     int64    `json:"asyncHandle"`
-	Cip             string   `json:"cip"`
-	Mip             string   `json:"mip"`
-	PendingNodeID   int64    `json:"pendingNodeID"`
-	PlatformInfo    Platform `json:"platformInfo"`
-	Sip             string   `json:"sip"`
-	SoftwareVersion string   `json:"softwareVersion"`
+	ActiveNodeKey   string        `json:"activeNodeKey"`
+	AssignedNodeID  int64         `json:"assignedNodeID"`
+	AsyncHandle     AsyncResultID `json:"asyncHandle"`
+	Cip             string        `json:"cip"`
+	Mip             string        `json:"mip"`
+	PendingNodeID   int64         `json:"pendingNodeID"`
+	PlatformInfo    Platform      `json:"platformInfo"`
+	Sip             string        `json:"sip"`
+	SoftwareVersion string        `json:"softwareVersion"`
 }
 
 type PendingNode struct {
@@ -803,6 +855,13 @@ type QoS struct {
 	BurstTime int64 `json:"burstTime,omitempty"`
 }
 
+type QoSPolicy struct {
+	QoSPolicyID int64     `json:"qosPolicyID"`
+	Name        string    `json:"name"`
+	Qos         VolumeQOS `json:"qos"`
+	VolumeIDs   []int64   `json:"volumeIDs"`
+}
+
 type RemoteReplication struct {
 	Mode                string              `json:"mode"`
 	PauseLimit          int64               `json:"pauseLimit"`
@@ -840,19 +899,28 @@ type RtfiInfo struct {
 	Options          interface{} `json:"options,omitempty"`
 }
 
+type RtfiPendingNodeAsyncResult struct {
+	PendingNodeID  int64    `json:"pendingNodeID"`
+	AssignedNodeID int64    `json:"assignedNodeID,omitempty"`
+	ActiveNodeKey  string   `json:"activeNodeKey,omitempty"`
+	Message        string   `json:"message,omitempty"`
+	RtfiInfo       RtfiInfo `json:"rtfiInfo,omitempty"`
+}
+
 type Schedule struct {
-	LastRunTimeStarted string       `json:"lastRunTimeStarted,omitempty"`
-	HasError           bool         `json:"hasError,omitempty"`
-	ScheduleInfo       ScheduleInfo `json:"scheduleInfo"`
-	RunNextInterval    bool         `json:"runNextInterval,omitempty"`
-	Name               string       `json:"name"`
-	LastRunStatus      string       `json:"lastRunStatus,omitempty"`
-	ScheduleID         int64        `json:"scheduleID,omitempty"`
-	Paused             bool         `json:"paused,omitempty"`
-	ToBeDeleted        bool         `json:"toBeDeleted,omitempty"`
-	Frequency          Frequency    `json:"frequency"`
-	StartingDate       string       `json:"startingDate,omitempty"`
-	Recurring          bool         `json:"recurring,omitempty"`
+	LastRunTimeStarted string                 `json:"lastRunTimeStarted,omitempty"`
+	HasError           bool                   `json:"hasError,omitempty"`
+	ScheduleInfo       ScheduleInfo           `json:"scheduleInfo"`
+	RunNextInterval    bool                   `json:"runNextInterval,omitempty"`
+	Name               string                 `json:"name"`
+	LastRunStatus      string                 `json:"lastRunStatus,omitempty"`
+	ScheduleID         int64                  `json:"scheduleID,omitempty"`
+	Paused             bool                   `json:"paused,omitempty"`
+	ToBeDeleted        bool                   `json:"toBeDeleted,omitempty"`
+	Frequency          Frequency              `json:"frequency"`
+	StartingDate       string                 `json:"startingDate,omitempty"`
+	Recurring          bool                   `json:"recurring,omitempty"`
+	Attributes         map[string]interface{} `json:"attributes,omitempty"`
 }
 
 type ScheduleInfo struct {
@@ -1111,6 +1179,7 @@ type Volume struct {
 	ScsiEUIDeviceID    string       `json:"scsiEUIDeviceID"`
 	ScsiNAADeviceID    string       `json:"scsiNAADeviceID"`
 	Qos                VolumeQOS    `json:"qos"`
+	QosPolicyID        int64        `json:"qosPolicyID,omitempty"`
 	VolumeAccessGroups []int64      `json:"volumeAccessGroups"`
 	VolumePairs        []VolumePair `json:"volumePairs"`
 	DeleteTime         string       `json:"deleteTime,omitempty"`
@@ -2,8 +2,8 @@ package api
 
 type AddAccountRequest struct {
 	Username        string      `json:"username"`
-	InitiatorSecret CHAPSecret  `json:"initiatorSecret,omitempty"`
-	TargetSecret    CHAPSecret  `json:"targetSecret,omitempty"`
+	InitiatorSecret *CHAPSecret `json:"initiatorSecret,omitempty"`
+	TargetSecret    *CHAPSecret `json:"targetSecret,omitempty"`
 	Attributes      interface{} `json:"attributes,omitempty"`
 }
 
@@ -126,6 +126,11 @@ type CreateInitiatorsRequest struct {
 	Initiators []CreateInitiator `json:"initiators"`
 }
 
+type CreateQoSPolicyRequest struct {
+	Name string `json:"name"`
+	Qos  QoS    `json:"qos"`
+}
+
 type CreateScheduleRequest struct {
 	Schedule Schedule `json:"schedule"`
 }
@@ -186,6 +191,10 @@ type DeleteInitiatorsRequest struct {
 	Initiators []int64 `json:"initiators"`
 }
 
+type DeleteQoSPolicyRequest struct {
+	QoSPolicyID int64 `json:"qosPolicyID"`
+}
+
 type DeleteSnapshotRequest struct {
 	SnapshotID int64 `json:"snapshotID"`
 }
@@ -288,6 +297,10 @@ type GetNvramInfoRequest struct {
 	Force bool `json:"force,omitempty"`
 }
 
+type GetQoSPolicyRequest struct {
+	QoSPolicyID int64 `json:"qosPolicyID"`
+}
+
 type GetScheduleRequest struct {
 	ScheduleID int64 `json:"scheduleID"`
 }
@@ -470,8 +483,8 @@ type ModifyAccountRequest struct {
 	AccountID       int64       `json:"accountID"`
 	Username        string      `json:"username,omitempty"`
 	Status          string      `json:"status,omitempty"`
-	InitiatorSecret CHAPSecret  `json:"initiatorSecret,omitempty"`
-	TargetSecret    CHAPSecret  `json:"targetSecret,omitempty"`
+	InitiatorSecret *CHAPSecret `json:"initiatorSecret,omitempty"`
+	TargetSecret    *CHAPSecret `json:"targetSecret,omitempty"`
 	Attributes      interface{} `json:"attributes,omitempty"`
 }
 
@@ -504,6 +517,12 @@ type ModifyInitiatorsRequest struct {
 	Initiators []ModifyInitiator `json:"initiators"`
 }
 
+type ModifyQoSPolicyRequest struct {
+	QoSPolicyID int64  `json:"qosPolicyID"`
+	Name        string `json:"name,omitempty"`
+	Qos         QoS    `json:"qos,omitempty"`
+}
+
 type ModifyScheduleRequest struct {
 	Schedule Schedule `json:"schedule"`
 }
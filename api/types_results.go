@@ -10,7 +10,7 @@ type AddClusterAdminResult struct {
 }
 
 type AddDrivesResult struct {
-	AsyncHandle int64 `json:"asyncHandle,omitempty"`
+	AsyncHandle AsyncResultID `json:"asyncHandle,omitempty"`
 }
 
 type AddInitiatorsToVolumeAccessGroupResult struct {
@@ -35,20 +35,20 @@ type AddVolumesToVolumeAccessGroup struct {
 }
 
 type AsyncHandleResult struct {
-	AsyncHandle int64 `json:"asyncHandle"`
+	AsyncHandle AsyncResultID `json:"asyncHandle"`
 }
 
 type CloneMultipleVolumesResult struct {
-	AsyncHandle  int64                    `json:"asyncHandle"`
+	AsyncHandle  AsyncResultID            `json:"asyncHandle"`
 	GroupCloneID int64                    `json:"groupCloneID"`
 	Members      []GroupCloneVolumeMember `json:"members"`
 }
 
 type CloneVolumeResult struct {
-	Volume      Volume `json:"volume,omitempty"`
-	CloneID     int64  `json:"cloneID"`
-	VolumeID    int64  `json:"volumeID"`
-	AsyncHandle int64  `json:"asyncHandle"`
+	Volume      Volume        `json:"volume,omitempty"`
+	CloneID     int64         `json:"cloneID"`
+	VolumeID    int64         `json:"volumeID"`
+	AsyncHandle AsyncResultID `json:"asyncHandle"`
 }
 
 type CompleteClusterPairingResult struct {
@@ -56,8 +56,8 @@ type CompleteClusterPairingResult struct {
 }
 
 type CopyVolumeResult struct {
-	CloneID     int64 `json:"cloneID"`
-	AsyncHandle int64 `json:"asyncHandle"`
+	CloneID     int64         `json:"cloneID"`
+	AsyncHandle AsyncResultID `json:"asyncHandle"`
 }
 
 type CreateBackupTargetResult struct {
@@ -74,6 +74,10 @@ type CreateInitiatorsResult struct {
 	Initiators []Initiator `json:"initiators"`
 }
 
+type CreateQoSPolicyResult struct {
+	QoSPolicy QoSPolicy `json:"qosPolicy"`
+}
+
 type CreateScheduleResult struct {
 	ScheduleID int64 `json:"scheduleID"`
 }
@@ -342,6 +346,10 @@ type GetPendingOperationResult struct {
 	PendingOperation PendingOperation `json:"pendingOperation"`
 }
 
+type GetQoSPolicyResult struct {
+	QoSPolicy QoSPolicy `json:"qosPolicy"`
+}
+
 type GetRemoteLoggingHostsResult struct {
 	RemoteHosts []LoggingServer `json:"remoteHosts"`
 }
@@ -522,6 +530,10 @@ type ListProtocolEndpointsResult struct {
 	ProtocolEndpoints []ProtocolEndpoint `json:"protocolEndpoints"`
 }
 
+type ListQoSPoliciesResult struct {
+	QoSPolicies []QoSPolicy `json:"qosPolicies"`
+}
+
 type ListSchedulesResult struct {
 	Schedules []Schedule `json:"schedules"`
 }
@@ -636,6 +648,10 @@ type ModifyInitiatorsResult struct {
 	Initiators []Initiator `json:"initiators"`
 }
 
+type ModifyQoSPolicyResult struct {
+	QoSPolicy QoSPolicy `json:"qosPolicy"`
+}
+
 type ModifyScheduleResult struct {
 	Schedule Schedule `json:"schedule,omitempty"`
 }
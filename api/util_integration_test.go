@@ -10,7 +10,8 @@ import (
 	"github.com/pkg/errors"
 )
 
-const IntegrationTestHelp = "Set $SOLIDFIRE_HOST, $SOLIDFIRE_USER, and $SOLIDFIRE_PASS to enable integration tests"
+const IntegrationTestHelp = "Set $SOLIDFIRE_HOST, $SOLIDFIRE_USER, and $SOLIDFIRE_PASS to enable integration tests. " +
+	"Set $SOLIDFIRE_CA_FILE to verify the cluster certificates, otherwise verification is skipped"
 
 func IntegrationTestsDisabled() bool {
 	host := os.Getenv("SOLIDFIRE_HOST")
@@ -33,6 +34,7 @@ func BuildTestClient(t *testing.T) *api.Client {
 		Username: username,
 		Password: password,
 	}
+	setTestTLSOptions(&opts)
 	c, err := api.BuildClient(opts)
 	if err != nil {
 		t.Fatalf("Error connecting: %s\n", err)
@@ -53,6 +55,7 @@ func BuildTestClientHost2(t *testing.T) *api.Client {
 		Username: username,
 		Password: password,
 	}
+	setTestTLSOptions(&opts)
 	c, err := api.BuildClient(opts)
 	if err != nil {
 		t.Fatalf("Error connecting: %s\n", err)
@@ -60,6 +63,13 @@ func BuildTestClientHost2(t *testing.T) *api.Client {
 	return c
 }
 
+// Test clusters usually have self-signed certificates, so verification is only enabled when a CA
+// bundle is provided
+func setTestTLSOptions(opts *api.ClientOptions) {
+	opts.CACertFile = os.Getenv("SOLIDFIRE_CA_FILE")
+	opts.InsecureSkipVerify = opts.CACertFile == ""
+}
+
 type EphemeralEntity struct {
 	Entity  interface{}
 	Destroy func()
@@ -34,6 +34,30 @@ func (c *Client) ModifyVolumePair(ctx context.Context, req ModifyVolumePairReque
 	return c.request(ctx, "ModifyVolumePair", req, nil)
 }
 
+// volumePairPauseParams always sends pausedManual, ModifyVolumePairRequest omits it when false
+type volumePairPauseParams struct {
+	VolumeID     int64 `json:"volumeID"`
+	PausedManual bool  `json:"pausedManual"`
+}
+
+// PauseVolumePair manually pauses the replication of a paired volume
+func (c *Client) PauseVolumePair(ctx context.Context, volId int64) (err error) {
+	req := volumePairPauseParams{
+		VolumeID:     volId,
+		PausedManual: true,
+	}
+	return c.request(ctx, "ModifyVolumePair", req, nil)
+}
+
+// ResumeVolumePair resumes a replication that was manually paused on this volume
+func (c *Client) ResumeVolumePair(ctx context.Context, volId int64) (err error) {
+	req := volumePairPauseParams{
+		VolumeID:     volId,
+		PausedManual: false,
+	}
+	return c.request(ctx, "ModifyVolumePair", req, nil)
+}
+
 func (c *Client) RemoveVolumePair(ctx context.Context, volId int64) (err error) {
 	req := RemoveVolumePairRequest{
 		VolumeID: volId,
@@ -128,3 +128,17 @@ func TestListActivePairedVolumes(t *testing.T) {
 	require.True(t, len(resp) > 0)
 	require.Equal(t, testVolumePairVolumeId, resp[0].VolumeID)
 }
+
+func TestPauseResumeVolumePair(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, buildSFResponseWrapper(map[string]interface{}{}))
+	defer mockReset()
+
+	ctx := context.Background()
+	require.Nil(t, c.PauseVolumePair(ctx, testVolumePairVolumeId))
+	require.Nil(t, c.ResumeVolumePair(ctx, testVolumePairVolumeId))
+	require.Equal(t, "ModifyVolumePair", (*requests)[0].Method)
+	require.Equal(t, true, (*requests)[0].Params["pausedManual"])
+	require.Equal(t, false, (*requests)[1].Params["pausedManual"])
+	require.Equal(t, float64(testVolumePairVolumeId), (*requests)[1].Params["volumeID"])
+}
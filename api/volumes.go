@@ -14,6 +14,11 @@ const (
 )
 
 func (c *Client) CreateVolume(ctx context.Context, req CreateVolumeRequest) (result *Volume, err error) {
+	if c.qosValidator != nil {
+		if err = c.qosValidator.ValidateCreateVolumeRequest(ctx, req); err != nil {
+			return nil, err
+		}
+	}
 	cvr := CreateVolumeResult{}
 	err = c.request(ctx, "CreateVolume", req, &cvr)
 	result = &cvr.Volume
@@ -21,12 +26,30 @@ func (c *Client) CreateVolume(ctx context.Context, req CreateVolumeRequest) (res
 }
 
 func (c *Client) ModifyVolume(ctx context.Context, req ModifyVolumeRequest) (result *Volume, err error) {
+	if c.qosValidator != nil {
+		if err = c.qosValidator.ValidateModifyVolumeRequest(ctx, req); err != nil {
+			return nil, err
+		}
+	}
 	mvr := ModifyVolumeResult{}
 	err = c.request(ctx, "ModifyVolume", req, &mvr)
 	result = &mvr.Volume
 	return result, err
 }
 
+// ModifyVolumes applies the same changes to every volume of req.VolumeIDs
+func (c *Client) ModifyVolumes(ctx context.Context, req ModifyVolumesRequest) (result []Volume, err error) {
+	if c.qosValidator != nil {
+		if err = c.qosValidator.ValidateModifyVolumesRequest(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	mvr := ModifyVolumesResult{}
+	err = c.request(ctx, "ModifyVolumes", req, &mvr)
+	result = mvr.Volumes
+	return result, err
+}
+
 func (c *Client) DeleteVolume(ctx context.Context, id int64) (result *Volume, err error) {
 	req := DeleteVolumeRequest{
 		VolumeID: id,
@@ -37,6 +60,43 @@ func (c *Client) DeleteVolume(ctx context.Context, id int64) (result *Volume, er
 	return result, err
 }
 
+// DeleteVolumes deletes the given volumes, or all volumes of the given accounts or volume access
+// groups. Only one of the three may be set. Deleted volumes can be restored until purged.
+func (c *Client) DeleteVolumes(ctx context.Context, req DeleteVolumesRequest) (result []Volume, err error) {
+	dvr := DeleteVolumesResult{}
+	err = c.request(ctx, "DeleteVolumes", req, &dvr)
+	result = dvr.Volumes
+	return result, err
+}
+
+func (c *Client) ListDeletedVolumes(ctx context.Context, req ListDeletedVolumesRequest) (result []Volume, err error) {
+	ldvr := ListDeletedVolumesResult{}
+	err = c.request(ctx, "ListDeletedVolumes", req, &ldvr)
+	result = ldvr.Volumes
+	return result, err
+}
+
+func (c *Client) RestoreDeletedVolume(ctx context.Context, id int64) (err error) {
+	req := RestoreDeletedVolumeRequest{
+		VolumeID: id,
+	}
+	return c.request(ctx, "RestoreDeletedVolume", req, nil)
+}
+
+// PurgeDeletedVolume permanently removes a deleted volume and frees its capacity
+func (c *Client) PurgeDeletedVolume(ctx context.Context, id int64) (err error) {
+	req := PurgeDeletedVolumeRequest{
+		VolumeID: id,
+	}
+	return c.request(ctx, "PurgeDeletedVolume", req, nil)
+}
+
+// PurgeDeletedVolumes permanently removes the given deleted volumes, or those of the given accounts
+// or volume access groups. Only one of the three may be set.
+func (c *Client) PurgeDeletedVolumes(ctx context.Context, req PurgeDeletedVolumesRequest) (err error) {
+	return c.request(ctx, "PurgeDeletedVolumes", req, nil)
+}
+
 func (c *Client) ListVolumes(ctx context.Context, req ListVolumesRequest) (result []Volume, err error) {
 	lvr := ListVolumesResult{}
 	err = c.request(ctx, "ListVolumes", req, &lvr)
@@ -44,6 +104,13 @@ func (c *Client) ListVolumes(ctx context.Context, req ListVolumesRequest) (resul
 	return result, err
 }
 
+func (c *Client) ListVolumesForAccount(ctx context.Context, req ListVolumesForAccountRequest) (result []Volume, err error) {
+	lvfar := ListVolumesForAccountResult{}
+	err = c.request(ctx, "ListVolumesForAccount", req, &lvfar)
+	result = lvfar.Volumes
+	return result, err
+}
+
 func (c *Client) GetVolumeById(ctx context.Context, id int64) (result *Volume, err error) {
 	req := ListVolumesRequest{
 		VolumeIDs: []int64{id},
@@ -163,3 +163,83 @@ func TestGetVolumeByIdError(t *testing.T) {
 	require.True(t, errors.As(err, &reqErr))
 	require.Equal(t, ErrVolumeIDDoesNotExist, reqErr.Name)
 }
+
+func testDeletedVolume(id int64) map[string]interface{} {
+	volume := make(map[string]interface{})
+	for k, v := range testVolume {
+		volume[k] = v
+	}
+	volume["volumeID"] = id
+	volume["status"] = "deleted"
+	volume["deleteTime"] = "2021-06-01T10:00:00Z"
+	volume["purgeTime"] = "2021-06-01T18:00:00Z"
+	return volume
+}
+
+func TestModifyVolumes(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, buildSFResponseWrapper(map[string]interface{}{
+		"Volumes": []map[string]interface{}{testVolume, testVolume},
+	}))
+	defer mockReset()
+
+	ctx := context.Background()
+	req := ModifyVolumesRequest{
+		VolumeIDs: []int64{testVolumeId, testVolumeId + 1},
+		Access:    VolumeAccessPolicyReadOnly,
+	}
+	resp, err := c.ModifyVolumes(ctx, req)
+	require.Nil(t, err)
+	require.Len(t, resp, 2)
+	require.Equal(t, "ModifyVolumes", (*requests)[0].Method)
+	require.Equal(t, []interface{}{float64(testVolumeId), float64(testVolumeId + 1)}, (*requests)[0].Params["volumeIDs"])
+	require.Equal(t, VolumeAccessPolicyReadOnly, (*requests)[0].Params["access"])
+}
+
+func TestDeleteVolumes(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, buildSFResponseWrapper(map[string]interface{}{
+		"Volumes": []map[string]interface{}{testDeletedVolume(testVolumeId), testDeletedVolume(testVolumeId + 1)},
+	}))
+	defer mockReset()
+
+	ctx := context.Background()
+	resp, err := c.DeleteVolumes(ctx, DeleteVolumesRequest{AccountIDs: []int64{testAccountId}})
+	require.Nil(t, err)
+	require.Len(t, resp, 2)
+	require.Equal(t, "deleted", resp[1].Status)
+	require.Equal(t, []interface{}{float64(testAccountId)}, (*requests)[0].Params["accountIDs"])
+	require.NotContains(t, (*requests)[0].Params, "volumeIDs")
+}
+
+func TestListDeletedVolumes(t *testing.T) {
+	c := getTestClient(t)
+	mockResp := buildSFResponseWrapper(map[string]interface{}{"Volumes": []map[string]interface{}{testDeletedVolume(testVolumeId)}})
+	mockReset := activateMock(t, c, mockResp)
+	defer mockReset()
+
+	resp, err := c.ListDeletedVolumes(context.Background(), ListDeletedVolumesRequest{})
+	require.Nil(t, err)
+	require.Equal(t, testVolumeId, resp[0].VolumeID)
+	require.Equal(t, "2021-06-01T18:00:00Z", resp[0].PurgeTime)
+}
+
+func TestRestoreAndPurgeDeletedVolumes(t *testing.T) {
+	c := getTestClient(t)
+	requests, mockReset := activateRecordingMock(c, buildSFResponseWrapper(map[string]interface{}{}))
+	defer mockReset()
+
+	ctx := context.Background()
+	require.Nil(t, c.RestoreDeletedVolume(ctx, testVolumeId))
+	require.Nil(t, c.PurgeDeletedVolume(ctx, testVolumeId+1))
+	require.Nil(t, c.PurgeDeletedVolumes(ctx, PurgeDeletedVolumesRequest{VolumeIDs: []int64{testVolumeId + 2, testVolumeId + 3}}))
+	require.Equal(t, []string{"RestoreDeletedVolume", "PurgeDeletedVolume", "PurgeDeletedVolumes"}, requestMethods(*requests))
+	require.Equal(t, float64(testVolumeId), (*requests)[0].Params["volumeID"])
+	require.Equal(t, float64(testVolumeId+1), (*requests)[1].Params["volumeID"])
+	require.Len(t, (*requests)[2].Params["volumeIDs"], 2)
+
+	activateMock(t, c, SFResponse{Error: SFAPIError{Code: 500, Name: ErrVolumeIDDoesNotExist, Message: "volume does not exist"}})
+	err := c.RestoreDeletedVolume(ctx, testVolumeId)
+	var notFound *ResourceNotFoundError
+	require.True(t, errors.As(err, &notFound))
+}
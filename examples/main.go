@@ -98,6 +98,12 @@ func main() {
 		Username: username,
 		Password: password,
 	}
+	if caFile := os.Getenv("SOLIDFIRE_CA_FILE"); caFile != "" {
+		opts.CACertFile = caFile
+	} else {
+		fmt.Println("WARNING: $SOLIDFIRE_CA_FILE is not set, skipping TLS certificate verification")
+		opts.InsecureSkipVerify = true
+	}
 	c, err := api.BuildClient(opts)
 	if err != nil {
 		fmt.Printf("Error connecting: %s\n", err)